@@ -0,0 +1,465 @@
+// Package checker evaluates checks independently of any web framework. The
+// handlers of the controllers package serve the reports of a Checker or a
+// Scheduler over HTTP; a Checker can as well be run from a CLI, a gRPC
+// server or a startup routine.
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/events"
+)
+
+var (
+	// ErrUnknownCheck is returned for references that match no check.
+	ErrUnknownCheck = errors.New("unknown check")
+	// ErrAmbiguousCheck is returned for references that match more than one
+	// check, e.g. the derived ID of two checks with the same name.
+	ErrAmbiguousCheck = errors.New("ambiguous check")
+)
+
+// Runner is implemented by Checker, which runs the checks on every call,
+// and by Scheduler, which returns their latest results.
+type Runner interface {
+	Config() config.Config
+	Run(ctx context.Context) Report
+	RunFilter(ctx context.Context, filter Filter) (Report, error)
+	RunCheck(ctx context.Context, ref string) (Report, error)
+	Find(ref string) (checks.Check, error)
+}
+
+// Checker evaluates checks according to a config.Config. It keeps the state
+// needed across evaluations, like thresholds, notification windows and
+// status changes for events, so a Checker should be reused.
+type Checker struct {
+	checks func() []checks.Check
+	config config.Config
+	probe  checks.Probe
+
+	lock          sync.Mutex
+	failureInARow uint32
+	statuses      map[string]checks.Status
+	hysteresis    map[string]*hysteresis
+	window        slidingWindow
+	windows       map[string]*slidingWindow
+	aggregate     checks.Status
+	// notified is closed when the latest call of notify returned.
+	notified chan struct{}
+
+	// inFlight is the running evaluation, last the latest one.
+	inFlight *flight
+	last     *flight
+}
+
+// flight is an evaluation shared by concurrent callers.
+type flight struct {
+	start  time.Time
+	done   chan struct{}
+	report Report
+}
+
+// New returns a Checker of checkList.
+func New(checkList []checks.Check, config config.Config) *Checker {
+	return newChecker(func() []checks.Check {
+		return checkList
+	}, config)
+}
+
+// NewFromRegistry returns a Checker of the checks registered in registry at
+// the time of each evaluation.
+func NewFromRegistry(registry *checks.Registry, config config.Config) *Checker {
+	return newChecker(registry.List, config)
+}
+
+// NewProbe returns a Checker of the checks of checkList that belong to probe
+// (see checks.WithProbes), with the status codes configured for probe. Its
+// events carry probe. Failure notifications are only sent for the readiness
+// probe, which covers the dependencies of the service.
+func NewProbe(probe checks.Probe, checkList []checks.Check, config config.Config) *Checker {
+	var probeChecks []checks.Check
+	for _, check := range checkList {
+		if checks.OptionsOf(check).HasProbe(probe) {
+			probeChecks = append(probeChecks, check)
+		}
+	}
+
+	config = config.Probe(probe)
+	if probe != checks.Readiness {
+		config.FailureNotification.Chan = nil
+	}
+	c := New(probeChecks, config)
+	c.probe = probe
+	return c
+}
+
+func newChecker(source func() []checks.Check, config config.Config) *Checker {
+	return &Checker{
+		checks:     source,
+		config:     config,
+		statuses:   make(map[string]checks.Status),
+		hysteresis: make(map[string]*hysteresis),
+		windows:    make(map[string]*slidingWindow),
+	}
+}
+
+// Config returns the configuration of c.
+func (c *Checker) Config() config.Config {
+	return c.config
+}
+
+// Run evaluates all but the deep checks and sends aggregate events and
+// failure notifications. Evaluations are shared between concurrent callers
+// according to config.Config.Coalesce and MinInterval.
+func (c *Checker) Run(ctx context.Context) Report {
+	checkList, deep := Filter{}.selectChecks(c.checks())
+	evaluate := func(ctx context.Context) Report {
+		report := c.evaluate(ctx, checkList, true)
+		report.SkippedDeep = deep
+		return report
+	}
+	if !c.config.Coalesce && c.config.MinInterval <= 0 {
+		return evaluate(ctx)
+	}
+
+	c.lock.Lock()
+	if last := c.last; last != nil && time.Since(last.start) < c.config.MinInterval {
+		c.lock.Unlock()
+		report := last.report
+		report.Cached = true
+		return report
+	}
+	if running := c.inFlight; running != nil && c.config.Coalesce {
+		c.lock.Unlock()
+		select {
+		case <-running.done:
+			report := running.report
+			report.Shared = true
+			return report
+		case <-ctx.Done():
+			return c.failed(checkList, ctx.Err())
+		}
+	}
+	// Waiters get a failed report should the evaluation not complete.
+	f := &flight{
+		start:  time.Now(),
+		done:   make(chan struct{}),
+		report: c.failed(checkList, ErrHealthcheckFailed),
+	}
+	c.inFlight = f
+	c.lock.Unlock()
+
+	completed := false
+	defer func() {
+		c.lock.Lock()
+		if c.inFlight == f {
+			c.inFlight = nil
+		}
+		if completed {
+			c.last = f
+		}
+		c.lock.Unlock()
+		close(f.done)
+	}()
+
+	// The evaluation must not be cancelled with the caller that started it
+	// while others wait for it, so it is bounded by its own deadline.
+	detached := context.WithoutCancel(ctx)
+	if c.config.Timeout <= 0 {
+		var cancel context.CancelFunc
+		detached, cancel = context.WithTimeout(detached, config.DefaultCoalesceTimeout)
+		defer cancel()
+	}
+	f.report = evaluate(detached)
+	completed = true
+	return f.report
+}
+
+// failed reports every check of checkList as failed with err, without
+// running them.
+func (c *Checker) failed(checkList []checks.Check, err error) Report {
+	now := time.Now().UTC()
+	statuses := make([]CheckStatus, 0, len(checkList))
+	for _, check := range checkList {
+		options := checks.OptionsOf(check)
+		statuses = append(statuses, CheckStatus{
+			Name:          check.Name(),
+			Status:        checks.StatusFail,
+			Pass:          false,
+			Error:         c.config.ErrorDetail.Message(err),
+			Timestamp:     now,
+			Optional:      options.Optional,
+			componentType: options.ComponentType,
+		})
+	}
+	return newReport(statuses, c.config.Policy)
+}
+
+// RunFilter evaluates the checks selected by filter, or is Run for an empty
+// filter. It returns ErrUnknownTag for tags no check is tagged with.
+// Filtered evaluations do not send aggregate events and notifications.
+func (c *Checker) RunFilter(ctx context.Context, filter Filter) (Report, error) {
+	if filter.IsEmpty() {
+		return c.Run(ctx), nil
+	}
+
+	checkList := c.checks()
+	if err := filter.validate(checkList); err != nil {
+		return Report{}, err
+	}
+
+	selected, deep := filter.selectChecks(checkList)
+	report := c.evaluate(ctx, selected, false)
+	report.Filter = &filter
+	report.SkippedDeep = deep
+	return report, nil
+}
+
+// RunCheck evaluates only the check with the ID (see checks.IDOf) or name
+// ref, deep or not. It returns ErrUnknownCheck if there is none and
+// ErrAmbiguousCheck if there is more than one.
+func (c *Checker) RunCheck(ctx context.Context, ref string) (Report, error) {
+	check, err := c.Find(ref)
+	if err != nil {
+		return Report{}, err
+	}
+	return c.evaluate(ctx, []checks.Check{check}, false), nil
+}
+
+// Find returns the check with the ID or name ref, or ErrUnknownCheck or
+// ErrAmbiguousCheck.
+func (c *Checker) Find(ref string) (checks.Check, error) {
+	return find(c.checks(), ref)
+}
+
+func find(checkList []checks.Check, ref string) (checks.Check, error) {
+	idx, err := findIndex(checkList, ref)
+	if err != nil {
+		return nil, err
+	}
+	return checkList[idx], nil
+}
+
+// findIndex returns the index of the only check of checkList ref refers to,
+// or ErrUnknownCheck or ErrAmbiguousCheck.
+func findIndex(checkList []checks.Check, ref string) (int, error) {
+	matches := checks.Matches(checkList, ref)
+	switch len(matches) {
+	case 0:
+		return -1, fmt.Errorf("%w %q", ErrUnknownCheck, ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return -1, fmt.Errorf("%w %q: it refers to %d checks", ErrAmbiguousCheck, ref, len(matches))
+	}
+}
+
+// evaluate runs checkList with runAll and, if notify is set, sends
+// aggregate events and failure notifications.
+func (c *Checker) evaluate(ctx context.Context, checkList []checks.Check, notify bool) Report {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	ctx = config.WithScope(ctx, config.Scope{Full: notify, Probe: c.probe})
+	for _, hook := range c.config.Hooks {
+		ctx = hook.StartEvaluation(ctx)
+	}
+
+	statuses := c.runAll(ctx, checkList)
+
+	report := newReport(statuses, c.config.Policy)
+
+	for _, hook := range c.config.Hooks {
+		hook.EndEvaluation(ctx, report.Status)
+	}
+	if notify {
+		c.notify(report.Status)
+	}
+
+	return report
+}
+
+// run evaluates a single check within config.CheckTimeout. Panics of the
+// check are recovered and reported as failures.
+func (c *Checker) run(ctx context.Context, check checks.Check) CheckStatus {
+	name := check.Name()
+	for _, hook := range c.config.Hooks {
+		ctx = hook.StartCheck(ctx, name)
+	}
+
+	ctx, complete := checks.Collect(ctx)
+	start := time.Now()
+	err := runCheck(ctx, check, c.config)
+	duration := time.Since(start)
+	raw := checks.StatusOf(err)
+
+	for _, hook := range c.config.Hooks {
+		hook.EndCheck(ctx, name, raw, err, duration)
+	}
+
+	options := checks.OptionsOf(check)
+	status := c.smooth(name, raw, options)
+	c.transition(name, status, err)
+	c.observe(ctx, name, options, raw)
+
+	result := CheckStatus{
+		Name:          name,
+		Status:        status,
+		Pass:          status != checks.StatusFail,
+		Error:         c.config.ErrorDetail.Message(err),
+		Duration:      duration,
+		Timestamp:     start.UTC(),
+		Optional:      options.Optional,
+		componentType: options.ComponentType,
+	}
+	details := complete(checks.Result{Name: name, Err: err})
+	result.addDetails(details, c.config.ErrorDetail)
+	c.recovered(details)
+	if options.FailureThreshold > 1 || options.SuccessThreshold > 1 {
+		result.RawStatus = raw
+	}
+	return result
+}
+
+// skip reports check as skipped because the check named dependency failed.
+// The reason names no more than the dependency, so it is reported whatever
+// config.Config.ErrorDetail is.
+func (c *Checker) skip(ctx context.Context, check checks.Check, dependency string) CheckStatus {
+	name := check.Name()
+	err := fmt.Errorf("skipped: dependency %q failed", dependency)
+	options := checks.OptionsOf(check)
+	c.transition(name, checks.StatusFail, err)
+	c.observe(ctx, name, options, checks.StatusFail)
+
+	return CheckStatus{
+		Name:          name,
+		Status:        checks.StatusFail,
+		Pass:          false,
+		Error:         err.Error(),
+		Timestamp:     time.Now().UTC(),
+		Optional:      options.Optional,
+		Skipped:       true,
+		componentType: options.ComponentType,
+	}
+}
+
+// recovered passes the panics of the check with result and of the checks
+// nested in it to config.Config.PanicHandler.
+func (c *Checker) recovered(result checks.Result) {
+	if c.config.PanicHandler == nil {
+		return
+	}
+
+	var panicErr *checks.PanicError
+	if errors.As(result.Err, &panicErr) {
+		c.config.PanicHandler(result.Name, panicErr)
+	}
+	for _, child := range result.Children {
+		c.recovered(child)
+	}
+}
+
+// transition publishes an event when the status of a check changed.
+func (c *Checker) transition(name string, status checks.Status, err error) {
+	if c.config.Events == nil {
+		return
+	}
+
+	c.lock.Lock()
+	old := c.statuses[name]
+	c.statuses[name] = status
+	c.lock.Unlock()
+
+	if old != status {
+		c.config.Events.Publish(events.Event{
+			Check: name,
+			Probe: c.probe,
+			Old:   old,
+			New:   status,
+			Err:   err,
+			Time:  time.Now(),
+		})
+	}
+}
+
+// notify publishes changes of the aggregated status and sends failure
+// notifications, according to the notification window if configured.
+func (c *Checker) notify(status checks.Status) {
+	var err error
+	send, crossed := false, false
+
+	c.lock.Lock()
+	old := c.aggregate
+	c.aggregate = status
+	if window := c.config.FailureNotification.Window; window != nil {
+		reached, changed := c.window.add(*window, c.now(), status == checks.StatusFail)
+		if changed {
+			send, crossed = true, true
+			if reached {
+				err = ErrHealthcheckFailed
+			}
+		}
+	} else if status == checks.StatusFail {
+		c.failureInARow += 1
+
+		if c.failureInARow >= c.config.FailureNotification.Threshold {
+			err = ErrHealthcheckFailed
+			send = true
+		}
+	} else if c.failureInARow != 0 {
+		c.failureInARow = 0
+		send = true
+	}
+	// Events and notifications are sent in the order of the status
+	// changes: every call waits for the previous one, but not under c.lock.
+	previous := c.notified
+	notified := make(chan struct{})
+	c.notified = notified
+	c.lock.Unlock()
+
+	defer close(notified)
+	if previous != nil {
+		<-previous
+	}
+
+	if c.config.Events != nil && old != status {
+		event := events.Event{
+			Aggregate: true,
+			Probe:     c.probe,
+			Old:       old,
+			New:       status,
+			Time:      time.Now(),
+		}
+		if status == checks.StatusFail {
+			event.Err = ErrHealthcheckFailed
+		}
+		c.config.Events.Publish(event)
+	}
+	if c.config.Events != nil && crossed {
+		event := windowEvent(err != nil, err)
+		event.Aggregate = true
+		event.Probe = c.probe
+		c.config.Events.Publish(event)
+	}
+
+	if send && c.config.FailureNotification.Chan != nil {
+		c.config.FailureNotification.Chan <- err
+	}
+}
+
+func runCheck(ctx context.Context, check checks.Check, config config.Config) error {
+	if config.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.CheckTimeout)
+		defer cancel()
+	}
+
+	return checks.Run(ctx, check)
+}
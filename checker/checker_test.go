@@ -0,0 +1,159 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+type resultCheck struct {
+	name string
+	err  error
+}
+
+func (r resultCheck) Pass() bool {
+	return r.err == nil
+}
+
+func (r resultCheck) Check(ctx context.Context) error {
+	return r.err
+}
+
+func (r resultCheck) Name() string {
+	return r.name
+}
+
+var (
+	up   = resultCheck{name: "up"}
+	down = resultCheck{name: "down", err: errors.New("down")}
+)
+
+func TestCheckerRun(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.FailureNotification.Chan = make(chan error, 1)
+
+	report := New([]checks.Check{up, down}, conf).Run(context.Background())
+
+	assert.Equal(t, checks.StatusFail, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.True(t, report.Checks[0].Pass)
+	assert.False(t, report.Checks[1].Pass)
+	assert.Equal(t, "down", report.Checks[1].Error)
+	assert.Equal(t, ErrHealthcheckFailed, <-conf.FailureNotification.Chan)
+}
+
+// alternatingCheck fails every other run.
+type alternatingCheck struct {
+	runs *atomic.Int64
+}
+
+func (a alternatingCheck) Pass() bool {
+	return a.runs.Add(1)%2 == 0
+}
+
+func (a alternatingCheck) Name() string {
+	return "alternating"
+}
+
+func TestNotificationOrder(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.FailureNotification.Chan = make(chan error)
+	checker := New([]checks.Check{alternatingCheck{runs: new(atomic.Int64)}}, conf)
+
+	const runs = 100
+	received := make(chan []error)
+	go func() {
+		var errs []error
+		for err := range conf.FailureNotification.Chan {
+			errs = append(errs, err)
+		}
+		received <- errs
+	}()
+
+	var wg sync.WaitGroup
+	for range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checker.Run(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(conf.FailureNotification.Chan)
+
+	// Concurrent runs wait for each other's notifications without
+	// deadlocking. A recovery is only sent after a failure, so two of them
+	// can only follow each other if notifications are reordered.
+	errs := <-received
+	assert.NotEmpty(t, errs)
+	for idx := 1; idx < len(errs); idx++ {
+		assert.False(t, errs[idx-1] == nil && errs[idx] == nil, "recoveries at %d and %d", idx-1, idx)
+	}
+}
+
+func TestReportJSON(t *testing.T) {
+	report := New([]checks.Check{
+		checks.Configure(up, checks.WithComponentType("datastore")),
+		checks.Configure(down, checks.Optional()),
+	}, config.DefaultConfig()).Run(context.Background())
+
+	body, err := json.Marshal(report)
+	assert.NoError(t, err)
+
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, checks.StatusPass, decoded.Status)
+	assert.Len(t, decoded.Checks, 2)
+	assert.Equal(t, "up", decoded.Checks[0].Name)
+	assert.True(t, decoded.Checks[1].Optional)
+	assert.Equal(t, "failed", decoded.Checks[1].Error, "errors are summarized by default")
+
+	response := NewHealthResponse(report, config.ServiceInfo{})
+	assert.Equal(t, "datastore", response.Checks["up:responseTime"][0].ComponentType)
+}
+
+func TestCheckerRunFilter(t *testing.T) {
+	checker := New([]checks.Check{
+		checks.Configure(up, checks.WithTags("db")),
+		down,
+	}, config.DefaultConfig())
+
+	report, err := checker.RunFilter(context.Background(), Filter{Include: []string{"db"}})
+	assert.NoError(t, err)
+	assert.Equal(t, checks.StatusPass, report.Status)
+	assert.Len(t, report.Checks, 1)
+	assert.Equal(t, &Filter{Include: []string{"db"}}, report.Filter)
+
+	_, err = checker.RunFilter(context.Background(), Filter{Include: []string{"cache"}})
+	assert.ErrorIs(t, err, ErrUnknownTag)
+	assert.EqualError(t, err, `unknown tag "cache"`)
+
+	report, err = checker.RunFilter(context.Background(), Filter{})
+	assert.NoError(t, err)
+	assert.Nil(t, report.Filter)
+	assert.Len(t, report.Checks, 2)
+}
+
+func TestCheckerRunCheck(t *testing.T) {
+	checker := New([]checks.Check{
+		up,
+		checks.Configure(down, checks.Deep()),
+	}, config.DefaultConfig())
+
+	report, err := checker.RunCheck(context.Background(), "down")
+	assert.NoError(t, err)
+	assert.Equal(t, checks.StatusFail, report.Status)
+	assert.Len(t, report.Checks, 1)
+
+	_, err = checker.RunCheck(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrUnknownCheck)
+	assert.EqualError(t, err, `unknown check "missing"`)
+}
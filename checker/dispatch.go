@@ -0,0 +1,118 @@
+package checker
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"golang.org/x/sync/errgroup"
+)
+
+// skipped reports whether the dependents of the check with dependency are
+// skipped. Failures of optional checks do not cascade, as they must not
+// fail the critical checks depending on them.
+func skipped(dependency CheckStatus) bool {
+	return dependency.Status == checks.StatusFail && !dependency.Optional
+}
+
+// runAll runs checkList in parallel, at most config.MaxConcurrency checks
+// at a time. Checks wait for the checks they depend on and are skipped if
+// one of the critical ones failed, see skipped. Ready checks start in the order of their priority:
+// critical checks before optional ones, each in list order.
+func (c *Checker) runAll(ctx context.Context, checkList []checks.Check) []CheckStatus {
+	// Dependencies are ignored if they form a cycle, which gin_healthcheck.New rejects.
+	deps, err := checks.Dependencies(checkList)
+	if err != nil {
+		deps = make([][]int, len(checkList))
+	}
+
+	pending := make([]int, len(checkList))
+	dependents := make([][]int, len(checkList))
+	for idx, list := range deps {
+		pending[idx] = len(list)
+		for _, dep := range list {
+			dependents[dep] = append(dependents[dep], idx)
+		}
+	}
+
+	limit := c.config.MaxConcurrency
+	if limit <= 0 {
+		limit = len(checkList)
+	}
+	priority := make([]int, len(checkList))
+	for idx, check := range checkList {
+		if checks.OptionsOf(check).Optional {
+			priority[idx] = 1
+		}
+	}
+
+	statuses := make([]CheckStatus, len(checkList))
+	readyAt := make([]time.Time, len(checkList))
+	var ready []int
+	remaining := len(checkList)
+
+	var finish func(idx int)
+	finish = func(idx int) {
+		remaining--
+		for _, dependent := range dependents[idx] {
+			pending[dependent]--
+			if pending[dependent] > 0 {
+				continue
+			}
+
+			failed := slices.IndexFunc(deps[dependent], func(dep int) bool {
+				return skipped(statuses[dep])
+			})
+			if failed >= 0 {
+				statuses[dependent] = c.skip(ctx, checkList[dependent], statuses[deps[dependent][failed]].Name)
+				finish(dependent)
+				continue
+			}
+			ready = append(ready, dependent)
+			readyAt[dependent] = time.Now()
+		}
+	}
+
+	now := time.Now()
+	for idx := range checkList {
+		if pending[idx] == 0 {
+			ready = append(ready, idx)
+			readyAt[idx] = now
+		}
+	}
+
+	var eg errgroup.Group
+	done := make(chan int)
+	running := 0
+	for remaining > 0 {
+		slices.SortStableFunc(ready, func(a, b int) int {
+			if priority[a] != priority[b] {
+				return priority[a] - priority[b]
+			}
+			return a - b
+		})
+		for running < limit && len(ready) > 0 {
+			idx := ready[0]
+			ready = ready[1:]
+			running++
+
+			eg.Go(func() error {
+				wait := time.Since(readyAt[idx])
+				statuses[idx] = c.run(ctx, checkList[idx])
+				if c.config.MaxConcurrency > 0 {
+					statuses[idx].Wait = wait
+				}
+				done <- idx
+				return nil
+			})
+		}
+
+		idx := <-done
+		running--
+		finish(idx)
+	}
+	eg.Wait()
+
+	return statuses
+}
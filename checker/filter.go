@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+// ErrUnknownTag is returned for filters with tags no check is tagged with.
+var ErrUnknownTag = errors.New("unknown tag")
+
+// Filter selects checks by their tags. A check is evaluated when it has at
+// least one of the included tags, or Include is empty, and none of the
+// excluded ones. Deep checks are only evaluated with Deep.
+type Filter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Deep    bool     `json:"deep,omitempty"`
+}
+
+// IsEmpty reports whether f selects the checks evaluated without a filter.
+func (f Filter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && !f.Deep
+}
+
+// validate returns an error for tags none of checkList is tagged with.
+func (f Filter) validate(checkList []checks.Check) error {
+	known := make(map[string]bool)
+	for _, check := range checkList {
+		for _, tag := range checks.OptionsOf(check).Tags {
+			known[tag] = true
+		}
+	}
+
+	for _, tag := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if !known[tag] {
+			return fmt.Errorf("%w %q", ErrUnknownTag, tag)
+		}
+	}
+	return nil
+}
+
+// selectChecks returns the checks of checkList f matches, and the names of
+// the deep checks it skips.
+func (f Filter) selectChecks(checkList []checks.Check) ([]checks.Check, []string) {
+	var selected []checks.Check
+	var deep []string
+	for _, check := range checkList {
+		if f.matches(check) {
+			selected = append(selected, check)
+		} else if f.skipsDeep(check) {
+			deep = append(deep, check.Name())
+		}
+	}
+	return selected, deep
+}
+
+// skipsDeep reports whether check is a deep check f would match if it was
+// Deep.
+func (f Filter) skipsDeep(check checks.Check) bool {
+	deep := f
+	deep.Deep = true
+	return !f.matches(check) && deep.matches(check)
+}
+
+func (f Filter) matches(check checks.Check) bool {
+	options := checks.OptionsOf(check)
+
+	if options.Deep && !f.Deep {
+		return false
+	}
+	for _, tag := range f.Exclude {
+		if options.HasTag(tag) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, tag := range f.Include {
+		if options.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
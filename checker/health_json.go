@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+const HealthJSONContentType = "application/health+json"
+
+// HealthResponse is the application/health+json representation of a
+// Report, as described in draft-inadarei-api-health-check.
+type HealthResponse struct {
+	Status      checks.Status                `json:"status"`
+	Version     string                       `json:"version,omitempty"`
+	ReleaseID   string                       `json:"releaseId,omitempty"`
+	ServiceID   string                       `json:"serviceId,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Checks      map[string][]HealthCheckItem `json:"checks"`
+}
+
+// HealthCheckItem is one entry of the checks object. The observed value of
+// every check is its response time.
+type HealthCheckItem struct {
+	ComponentType string        `json:"componentType,omitempty"`
+	ObservedValue float64       `json:"observedValue"`
+	ObservedUnit  string        `json:"observedUnit"`
+	Status        checks.Status `json:"status"`
+	Time          time.Time     `json:"time"`
+	Output        string        `json:"output,omitempty"`
+}
+
+// NewHealthResponse converts report to the application/health+json
+// representation, describing the service with service.
+func NewHealthResponse(report Report, service config.ServiceInfo) HealthResponse {
+	response := HealthResponse{
+		Status:      report.Status,
+		Version:     service.Version,
+		ReleaseID:   service.ReleaseID,
+		ServiceID:   service.ServiceID,
+		Description: service.Description,
+		Checks:      make(map[string][]HealthCheckItem, len(report.Checks)),
+	}
+
+	for _, status := range report.Checks {
+		key := status.Name + ":responseTime"
+		response.Checks[key] = append(response.Checks[key], HealthCheckItem{
+			ComponentType: status.componentType,
+			ObservedValue: float64(status.Duration) / float64(time.Millisecond),
+			ObservedUnit:  "ms",
+			Status:        status.Status,
+			Time:          status.Timestamp,
+			Output:        status.Error,
+		})
+	}
+
+	return response
+}
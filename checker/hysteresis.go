@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+// hysteresis is the smoothed state of a check with thresholds.
+type hysteresis struct {
+	status    checks.Status
+	failures  int
+	successes int
+}
+
+// smooth applies the thresholds of options to the raw status of the check
+// named name and returns the status to report. A check keeps its last
+// reported status until a threshold is reached.
+func (c *Checker) smooth(name string, raw checks.Status, options checks.Options) checks.Status {
+	if options.FailureThreshold <= 1 && options.SuccessThreshold <= 1 {
+		return raw
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h, ok := c.hysteresis[name]
+	if !ok {
+		h = &hysteresis{status: checks.StatusPass}
+		c.hysteresis[name] = h
+	}
+
+	if raw == checks.StatusFail {
+		h.failures++
+		h.successes = 0
+	} else {
+		h.successes++
+		h.failures = 0
+	}
+
+	switch {
+	case h.status != checks.StatusFail && raw == checks.StatusFail:
+		if h.failures >= options.FailureThreshold {
+			h.status = raw
+		}
+	case h.status == checks.StatusFail && raw != checks.StatusFail:
+		if h.successes >= options.SuccessThreshold {
+			h.status = raw
+		}
+	default:
+		h.status = raw
+	}
+	return h.status
+}
@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// CheckStatus is the result of a single check. Pass is false only for
+// failed checks. Duration is reported in nanoseconds, Error according to
+// config.Config.ErrorDetail. Optional checks do not affect the status of
+// the Report.
+type CheckStatus struct {
+	Name   string        `json:"name"`
+	Status checks.Status `json:"status"`
+	Pass   bool          `json:"pass"`
+	Error  string        `json:"error,omitempty"`
+	// Stack is the trimmed stack of a check that panicked. It is only
+	// reported with config.ErrorDetailFull.
+	Stack    string        `json:"stack,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// Wait is how long the check waited for one of the
+	// config.Config.MaxConcurrency slots, in nanoseconds.
+	Wait      time.Duration `json:"wait,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	// Age is set for results served from a Scheduler.
+	Age      time.Duration `json:"age,omitempty"`
+	Optional bool          `json:"optional,omitempty"`
+	// RawStatus is the status of the last run of checks with thresholds,
+	// whose Status only changes after a number of consecutive results.
+	RawStatus checks.Status `json:"rawStatus,omitempty"`
+	// Skipped is set for checks that did not run because a check they
+	// depend on failed.
+	Skipped bool `json:"skipped,omitempty"`
+	// Attempts is the number of runs of a check decorated with
+	// checks.Retry.
+	Attempts int `json:"attempts,omitempty"`
+	// Breaker is the state of the circuit of a check decorated with
+	// checks.Breaker.
+	Breaker *checks.BreakerState `json:"breaker,omitempty"`
+	// Checks are the results of the checks of an aggregate check.
+	Checks []CheckStatus `json:"checks,omitempty"`
+
+	componentType string
+}
+
+// Report is the result of an evaluation, and the response body of the
+// health endpoint. Status is decided by config.Config.Policy from the
+// statuses of all critical checks.
+type Report struct {
+	Status checks.Status `json:"status"`
+	Checks []CheckStatus `json:"checks"`
+	// Filter is the tag filter the checks were selected with, if any.
+	Filter *Filter `json:"filter,omitempty"`
+	// Shared is set when the report of a concurrent request was served,
+	// Cached when the report of an earlier one was. See
+	// config.Config.Coalesce and config.Config.MinInterval.
+	Shared bool `json:"shared,omitempty"`
+	Cached bool `json:"cached,omitempty"`
+	// SkippedDeep are the names of the deep checks that were not
+	// evaluated, because the request did not ask for them.
+	SkippedDeep []string `json:"skippedDeep,omitempty"`
+}
+
+var ErrHealthcheckFailed = errors.New("healthcheck failed")
+
+func newReport(statuses []CheckStatus, policy checks.Policy) Report {
+	if policy == nil {
+		policy = checks.PolicyAll()
+	}
+
+	var results []checks.Result
+	for _, status := range statuses {
+		if !status.Optional {
+			results = append(results, checks.Result{Name: status.Name, Status: status.Status})
+		}
+	}
+
+	return Report{
+		Status: checks.StatusOf(policy(results)),
+		Checks: statuses,
+	}
+}
+
+// addDetails adds the details recorded by aggregate checks and decorators,
+// and the stack of a panic.
+func (s *CheckStatus) addDetails(result checks.Result, detail config.ErrorDetail) {
+	var panicErr *checks.PanicError
+	if errors.As(result.Err, &panicErr) && detail == config.ErrorDetailFull {
+		s.Stack = panicErr.Stack
+	}
+	s.Attempts = result.Attempts
+	s.Breaker = result.Breaker
+	for _, child := range result.Children {
+		status := CheckStatus{
+			Name:      child.Name,
+			Status:    child.Status,
+			Pass:      child.Status != checks.StatusFail,
+			Error:     detail.Message(child.Err),
+			Duration:  child.Duration,
+			Timestamp: child.Timestamp,
+		}
+		status.addDetails(child, detail)
+		s.Checks = append(s.Checks, status)
+	}
+}
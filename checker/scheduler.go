@@ -0,0 +1,224 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// Scheduler runs checks in background goroutines, each on its own interval,
+// and keeps their latest results.
+type Scheduler struct {
+	checks  []checks.Check
+	deps    [][]int
+	checker *Checker
+
+	lock    sync.RWMutex
+	results []CheckStatus
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewScheduler(checkList []checks.Check, config config.Config) *Scheduler {
+	results := make([]CheckStatus, len(checkList))
+	for idx, check := range checkList {
+		options := checks.OptionsOf(check)
+		results[idx] = CheckStatus{
+			Name:          check.Name(),
+			Status:        checks.StatusFail,
+			Error:         config.ErrorDetail.Message(errNotRun),
+			Optional:      options.Optional,
+			componentType: options.ComponentType,
+		}
+	}
+
+	deps, err := checks.Dependencies(checkList)
+	if err != nil {
+		deps = make([][]int, len(checkList))
+	}
+
+	return &Scheduler{
+		checks:  checkList,
+		deps:    deps,
+		checker: New(checkList, config),
+		results: results,
+	}
+}
+
+var errNotRun = errors.New("check has not run yet")
+
+// Start runs every check right away and then on its interval, until ctx is
+// done or Stop is called. Starting a running scheduler has no effect.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	// Every run of the scheduler is part of its full evaluation.
+	ctx = config.WithScope(ctx, config.Scope{Full: true})
+
+	for idx, check := range s.checks {
+		interval := checks.OptionsOf(check).Interval
+		if interval <= 0 {
+			interval = s.checker.config.Scheduler.Interval
+		}
+		if interval <= 0 {
+			interval = config.DefaultSchedulerInterval
+		}
+
+		s.wg.Add(1)
+		go s.loop(ctx, idx, check, interval)
+	}
+}
+
+// Stop stops the background goroutines and waits for running checks to
+// return. The scheduler can be started again afterwards.
+func (s *Scheduler) Stop() {
+	s.lock.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.lock.Unlock()
+
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, idx int, check checks.Check, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := s.run(ctx, idx, check)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.lock.Lock()
+		s.results[idx] = status
+		s.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs check, unless the latest result of a check it depends on failed.
+func (s *Scheduler) run(ctx context.Context, idx int, check checks.Check) CheckStatus {
+	s.lock.RLock()
+	for _, dep := range s.deps[idx] {
+		result := s.results[dep]
+		if !result.Timestamp.IsZero() && skipped(result) {
+			s.lock.RUnlock()
+			return s.checker.skip(ctx, check, result.Name)
+		}
+	}
+	s.lock.RUnlock()
+
+	return s.checker.run(ctx, check)
+}
+
+// Report returns the latest results of all but the deep checks with their
+// age. Results older than config.Config.Scheduler.MaxAge are reported as
+// failed.
+func (s *Scheduler) Report() Report {
+	return s.report(Filter{})
+}
+
+func (s *Scheduler) report(filter Filter) Report {
+	s.lock.RLock()
+	var statuses []CheckStatus
+	var deep []string
+	for idx, check := range s.checks {
+		if filter.matches(check) {
+			statuses = append(statuses, s.results[idx])
+		} else if filter.skipsDeep(check) {
+			deep = append(deep, check.Name())
+		}
+	}
+	s.lock.RUnlock()
+
+	now := time.Now()
+	maxAge := s.checker.config.Scheduler.MaxAge
+	for idx, status := range statuses {
+		if status.Timestamp.IsZero() {
+			continue
+		}
+
+		statuses[idx].Age = now.Sub(status.Timestamp)
+		if maxAge > 0 && statuses[idx].Age > maxAge {
+			err := fmt.Errorf("result is older than %s", maxAge)
+			statuses[idx].Status = checks.StatusFail
+			statuses[idx].Pass = false
+			statuses[idx].Error = s.checker.config.ErrorDetail.Message(err)
+		}
+	}
+
+	if statuses == nil {
+		statuses = []CheckStatus{}
+	}
+	report := newReport(statuses, s.checker.config.Policy)
+	report.SkippedDeep = deep
+	return report
+}
+
+// Config returns the configuration of s.
+func (s *Scheduler) Config() config.Config {
+	return s.checker.config
+}
+
+// Run returns Report and sends aggregate events and failure notifications.
+func (s *Scheduler) Run(ctx context.Context) Report {
+	report := s.Report()
+	s.checker.notify(report.Status)
+	return report
+}
+
+// RunFilter returns the latest results of the checks selected by filter, or
+// is Run for an empty filter. It returns ErrUnknownTag for tags no check is
+// tagged with.
+func (s *Scheduler) RunFilter(ctx context.Context, filter Filter) (Report, error) {
+	if filter.IsEmpty() {
+		return s.Run(ctx), nil
+	}
+	if err := filter.validate(s.checks); err != nil {
+		return Report{}, err
+	}
+
+	report := s.report(filter)
+	report.Filter = &filter
+	return report, nil
+}
+
+// RunCheck returns the latest result of the check with the ID or name ref,
+// deep or not. It returns ErrUnknownCheck if there is none and
+// ErrAmbiguousCheck if there is more than one.
+func (s *Scheduler) RunCheck(ctx context.Context, ref string) (Report, error) {
+	idx, err := findIndex(s.checks, ref)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := s.report(Filter{Deep: true})
+	return newReport(report.Checks[idx:idx+1], s.checker.config.Policy), nil
+}
+
+// Find returns the check with the ID or name ref, or ErrUnknownCheck or
+// ErrAmbiguousCheck.
+func (s *Scheduler) Find(ref string) (checks.Check, error) {
+	return find(s.checks, ref)
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/events"
+)
+
+// CheckFailedError is sent on config.Config.FailureNotification.Chan when
+// the failures of a check reach the limits of the notification window.
+type CheckFailedError struct {
+	Check string
+}
+
+func (e *CheckFailedError) Error() string {
+	return fmt.Sprintf("check %q failed", e.Check)
+}
+
+func (e *CheckFailedError) Unwrap() error {
+	return ErrHealthcheckFailed
+}
+
+// CheckRecoveredError is sent on config.Config.FailureNotification.Chan when
+// the failures of a check drop below the limits of the notification window
+// again. Unlike CheckFailedError, it does not wrap ErrHealthcheckFailed.
+type CheckRecoveredError struct {
+	Check string
+}
+
+func (e *CheckRecoveredError) Error() string {
+	return fmt.Sprintf("check %q recovered", e.Check)
+}
+
+// slidingWindow keeps the results within a config.Window.
+type slidingWindow struct {
+	results []windowResult
+	reached bool
+}
+
+type windowResult struct {
+	time   time.Time
+	failed bool
+}
+
+// add records a result at now. It reports whether the limits of window are
+// reached and whether that changed with this result.
+func (s *slidingWindow) add(window config.Window, now time.Time, failed bool) (reached, changed bool) {
+	s.results = append(s.results, windowResult{time: now, failed: failed})
+	if window.Duration > 0 {
+		start := 0
+		for start < len(s.results) && now.Sub(s.results[start].time) > window.Duration {
+			start++
+		}
+		s.results = append(s.results[:0], s.results[start:]...)
+	} else if size := max(window.Size, 1); len(s.results) > size {
+		s.results = append(s.results[:0], s.results[len(s.results)-size:]...)
+	}
+
+	failures := 0
+	for _, result := range s.results {
+		if result.failed {
+			failures++
+		}
+	}
+
+	reached = failures >= max(window.Failures, 1) &&
+		(window.Ratio <= 0 || float64(failures)/float64(len(s.results)) > window.Ratio)
+	changed = reached != s.reached
+	s.reached = reached
+	return reached, changed
+}
+
+// observe applies the notification window to the check named name, if
+// configured for checks. Like the aggregated status, only full evaluations
+// count, and optional checks are left out as they must not notify.
+func (c *Checker) observe(ctx context.Context, name string, options checks.Options, status checks.Status) {
+	notification := c.config.FailureNotification
+	if notification.Window == nil || !notification.Checks {
+		return
+	}
+	if options.Optional || !config.ScopeOf(ctx).Full {
+		return
+	}
+
+	c.lock.Lock()
+	window, ok := c.windows[name]
+	if !ok {
+		window = &slidingWindow{}
+		c.windows[name] = window
+	}
+	reached, changed := window.add(*notification.Window, c.now(), status == checks.StatusFail)
+	c.lock.Unlock()
+	if !changed {
+		return
+	}
+
+	var err error = &CheckRecoveredError{Check: name}
+	if reached {
+		err = &CheckFailedError{Check: name}
+	}
+	if c.config.Events != nil {
+		event := windowEvent(reached, err)
+		event.Check = name
+		event.Probe = c.probe
+		c.config.Events.Publish(event)
+	}
+	// Unlike the notifications of the aggregated status, the ones of checks
+	// are dropped if the channel is not ready, as a check must not hold up
+	// the other checks of the evaluation.
+	if notification.Chan != nil {
+		select {
+		case notification.Chan <- err:
+		default:
+		}
+	}
+}
+
+// windowEvent is the event of the failures within a notification window
+// reaching its limits, or dropping below them if reached is false.
+func windowEvent(reached bool, err error) events.Event {
+	if reached {
+		return events.Event{Window: true, Old: checks.StatusPass, New: checks.StatusFail, Err: err, Time: time.Now()}
+	}
+	return events.Event{Window: true, Old: checks.StatusFail, New: checks.StatusPass, Time: time.Now()}
+}
+
+func (c *Checker) now() time.Time {
+	if c.config.FailureNotification.Clock != nil {
+		return c.config.FailureNotification.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+func TestCountWindow(t *testing.T) {
+	var w slidingWindow
+	window := config.Window{Size: 4, Failures: 2}
+	now := time.Now()
+
+	add := func(failed bool) (bool, bool) {
+		return w.add(window, now, failed)
+	}
+
+	assertAdd := func(failed, reached, changed bool) {
+		t.Helper()
+		r, c := add(failed)
+		assert.Equal(t, reached, r, "reached")
+		assert.Equal(t, changed, c, "changed")
+	}
+
+	assertAdd(true, false, false)
+	assertAdd(false, false, false)
+	assertAdd(true, true, true)
+	assertAdd(false, true, false)
+	assertAdd(false, false, true)
+	assertAdd(false, false, false)
+	assert.Len(t, w.results, 4)
+}
+
+func TestTimeWindow(t *testing.T) {
+	var w slidingWindow
+	window := config.Window{Duration: 5 * time.Minute, Ratio: 0.5}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	reached, _ := w.add(window, now, false)
+	assert.False(t, reached)
+	reached, _ = w.add(window, now.Add(time.Minute), true)
+	assert.False(t, reached, "50% is not more than 50%")
+	reached, changed := w.add(window, now.Add(2*time.Minute), true)
+	assert.True(t, reached)
+	assert.True(t, changed)
+
+	reached, changed = w.add(window, now.Add(7*time.Minute), false)
+	assert.False(t, reached, "only the last failure and the success are within the window")
+	assert.True(t, changed)
+	assert.Len(t, w.results, 2)
+}
@@ -0,0 +1,279 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one check of a group, as recorded by aggregate
+// checks and passed to a Policy.
+type Result struct {
+	Name      string
+	Status    Status
+	Err       error
+	Duration  time.Duration
+	Timestamp time.Time
+	// Children are the results of the checks of a nested aggregate check.
+	Children []Result
+	// Attempts is the number of runs of a check decorated with Retry.
+	Attempts int
+	// Breaker is the state of the circuit of a check decorated with Breaker.
+	Breaker *BreakerState
+}
+
+// Policy decides whether a group of checks passes from their results. It
+// returns nil if the group passes, and an error wrapped with Warn if it is
+// degraded.
+type Policy func(results []Result) error
+
+// PolicyAll passes if all checks pass. Its status is the most severe status
+// of all checks.
+func PolicyAll() Policy {
+	return func(results []Result) error {
+		status := StatusPass
+		failed := 0
+		for _, result := range results {
+			status = status.Worse(result.Status)
+			if result.Status != StatusPass {
+				failed++
+			}
+		}
+
+		switch status {
+		case StatusPass:
+			return nil
+		case StatusWarn:
+			return Warn(fmt.Errorf("%d of %d checks are degraded", failed, len(results)))
+		default:
+			return fmt.Errorf("%d of %d checks did not pass", failed, len(results))
+		}
+	}
+}
+
+// PolicyAtLeast passes if at least n checks did not fail. It is degraded if
+// that is the case, but some checks did not pass.
+func PolicyAtLeast(n int) Policy {
+	return func(results []Result) error {
+		passed, healthy := 0, 0
+		for _, result := range results {
+			if result.Status != StatusFail {
+				passed++
+			}
+			if result.Status == StatusPass {
+				healthy++
+			}
+		}
+
+		switch {
+		case passed < n:
+			return fmt.Errorf("%d of %d checks passed, %d required", passed, len(results), n)
+		case healthy < len(results):
+			return Warn(fmt.Errorf("%d of %d checks passed", healthy, len(results)))
+		default:
+			return nil
+		}
+	}
+}
+
+// PolicyAny passes if at least one check did not fail.
+func PolicyAny() Policy {
+	return PolicyAtLeast(1)
+}
+
+// PolicyWeighted passes if the weighted share of checks that did not fail is
+// at least threshold, a value between 0 and 1. weights maps check names to
+// their weight; checks that are not in weights have a weight of 1. It is
+// degraded if the threshold is reached, but some checks did not pass.
+func PolicyWeighted(threshold float64, weights map[string]float64) Policy {
+	return func(results []Result) error {
+		var total, passed float64
+		healthy := true
+		for _, result := range results {
+			weight, ok := weights[result.Name]
+			if !ok {
+				weight = 1
+			}
+
+			total += weight
+			if result.Status != StatusFail {
+				passed += weight
+			}
+			if result.Status != StatusPass {
+				healthy = false
+			}
+		}
+
+		score := 1.0
+		if total > 0 {
+			score = passed / total
+		}
+
+		switch {
+		case score < threshold:
+			return fmt.Errorf("score %.2f is below %.2f", score, threshold)
+		case !healthy:
+			return Warn(fmt.Errorf("score %.2f", score))
+		default:
+			return nil
+		}
+	}
+}
+
+type aggregateCheck struct {
+	name   string
+	policy Policy
+	checks []Check
+}
+
+// Aggregate returns a check named name that runs checks in parallel and
+// decides its result with policy. The results of checks are reported
+// nested in the result of the aggregate check.
+func Aggregate(name string, policy Policy, checks ...Check) Check {
+	return &aggregateCheck{name: name, policy: policy, checks: checks}
+}
+
+// AllOf passes if all checks pass.
+func AllOf(name string, checks ...Check) Check {
+	return Aggregate(name, PolicyAll(), checks...)
+}
+
+// AnyOf passes if at least one of checks passes.
+func AnyOf(name string, checks ...Check) Check {
+	return Aggregate(name, PolicyAny(), checks...)
+}
+
+// AtLeast passes if at least n of checks pass, e.g. a quorum of replicas.
+func AtLeast(name string, n int, checks ...Check) Check {
+	return Aggregate(name, PolicyAtLeast(n), checks...)
+}
+
+// WeightedCheck is a check of a Weighted check.
+type WeightedCheck struct {
+	Check  Check
+	Weight float64
+}
+
+// Weighted passes if the weighted share of checks that pass is at least
+// threshold, a value between 0 and 1.
+func Weighted(name string, threshold float64, checks ...WeightedCheck) Check {
+	weights := make(map[string]float64, len(checks))
+	list := make([]Check, len(checks))
+	for idx, check := range checks {
+		weights[check.Check.Name()] = check.Weight
+		list[idx] = check.Check
+	}
+	return Aggregate(name, PolicyWeighted(threshold, weights), list...)
+}
+
+func (a *aggregateCheck) Pass() bool {
+	return StatusOf(a.Check(context.Background())) != StatusFail
+}
+
+func (a *aggregateCheck) Check(ctx context.Context) error {
+	results := make([]Result, len(a.checks))
+
+	var wg sync.WaitGroup
+	for idx, check := range a.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx] = RunCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	record(ctx, results)
+	return a.policy(results)
+}
+
+func (a *aggregateCheck) Name() string {
+	return a.name
+}
+
+type notCheck struct {
+	check Check
+}
+
+// Not passes if c fails, and fails otherwise.
+func Not(c Check) Check {
+	return notCheck{check: c}
+}
+
+func (n notCheck) Pass() bool {
+	return n.Check(context.Background()) == nil
+}
+
+func (n notCheck) Check(ctx context.Context) error {
+	result := RunCheck(ctx, n.check)
+	record(ctx, []Result{result})
+
+	if result.Status != StatusFail {
+		return fmt.Errorf("%s did not fail", result.Name)
+	}
+	return nil
+}
+
+func (n notCheck) Name() string {
+	return "not " + n.check.Name()
+}
+
+// RunCheck runs c with Run and returns its result, including the details
+// recorded by aggregate checks and decorators.
+func RunCheck(ctx context.Context, c Check) Result {
+	ctx, complete := Collect(ctx)
+
+	start := time.Now()
+	err := Run(ctx, c)
+	return complete(Result{
+		Name:      c.Name(),
+		Status:    StatusOf(err),
+		Err:       err,
+		Duration:  time.Since(start),
+		Timestamp: start.UTC(),
+	})
+}
+
+type collectorKey struct{}
+
+type collector struct {
+	lock     sync.Mutex
+	children []Result
+	attempts int
+	breaker  *BreakerState
+}
+
+// Collect returns a context in which aggregate checks and decorators record
+// details of a run, and a function adding them to the result of the check
+// run with the context returned. Only the details of the last run of an
+// aggregate check are kept.
+func Collect(ctx context.Context) (context.Context, func(Result) Result) {
+	c := &collector{}
+	return context.WithValue(ctx, collectorKey{}, c), func(result Result) Result {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		result.Children = c.children
+		result.Attempts = c.attempts
+		result.Breaker = c.breaker
+		return result
+	}
+}
+
+func collect(ctx context.Context, fn func(c *collector)) {
+	c, ok := ctx.Value(collectorKey{}).(*collector)
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	fn(c)
+	c.lock.Unlock()
+}
+
+func record(ctx context.Context, results []Result) {
+	collect(ctx, func(c *collector) {
+		c.children = results
+	})
+}
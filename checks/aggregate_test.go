@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type resultCheck struct {
+	name string
+	err  error
+}
+
+func (r resultCheck) Pass() bool {
+	return r.err == nil
+}
+
+func (r resultCheck) Check(ctx context.Context) error {
+	return r.err
+}
+
+func (r resultCheck) Name() string {
+	return r.name
+}
+
+var (
+	up       = resultCheck{name: "up"}
+	down     = resultCheck{name: "down", err: errors.New("down")}
+	degraded = resultCheck{name: "degraded", err: Warn(errors.New("slow"))}
+)
+
+func TestAggregateChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check Check
+		want  Status
+		err   string
+	}{
+		{"all pass", AllOf("all", up, up), StatusPass, ""},
+		{"all degraded", AllOf("all", up, degraded), StatusWarn, "1 of 2 checks are degraded"},
+		{"all fail", AllOf("all", up, degraded, down), StatusFail, "2 of 3 checks did not pass"},
+		{"any pass", AnyOf("any", down, up), StatusWarn, "1 of 2 checks passed"},
+		{"any fail", AnyOf("any", down, down), StatusFail, "0 of 2 checks passed, 1 required"},
+		{"quorum", AtLeast("quorum", 2, up, up, down), StatusWarn, "2 of 3 checks passed"},
+		{"quorum fail", AtLeast("quorum", 2, up, down, down), StatusFail, "1 of 3 checks passed, 2 required"},
+		{"weighted", Weighted("weighted", 0.7, WeightedCheck{up, 3}, WeightedCheck{down, 1}), StatusWarn, "score 0.75"},
+		{"weighted fail", Weighted("weighted", 0.7, WeightedCheck{up, 1}, WeightedCheck{down, 1}), StatusFail, "score 0.50 is below 0.70"},
+		{"not pass", Not(down), StatusPass, ""},
+		{"not fail", Not(up), StatusFail, "up did not fail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AsContextCheck(tt.check).Check(context.Background())
+			assert.Equal(t, tt.want, StatusOf(err))
+			assert.Equal(t, tt.want != StatusFail, tt.check.Pass())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestAggregateRecordsNestedResults(t *testing.T) {
+	check := AllOf("all", up, AnyOf("any", down, degraded))
+
+	result := RunCheck(context.Background(), check)
+
+	assert.Equal(t, "all", result.Name)
+	assert.Equal(t, StatusWarn, result.Status)
+	if assert.Len(t, result.Children, 2) {
+		assert.Equal(t, "up", result.Children[0].Name)
+		assert.Empty(t, result.Children[0].Children)
+
+		any := result.Children[1]
+		assert.Equal(t, "any", any.Name)
+		assert.Equal(t, StatusWarn, any.Status)
+		if assert.Len(t, any.Children, 2) {
+			assert.Equal(t, StatusFail, any.Children[0].Status)
+			assert.EqualError(t, any.Children[0].Err, "down")
+			assert.Equal(t, StatusWarn, any.Children[1].Status)
+		}
+	}
+}
+
+func TestPolicyWeightedDefaultsToWeightOne(t *testing.T) {
+	policy := PolicyWeighted(0.5, map[string]float64{"down": 0.5})
+
+	assert.NoError(t, policy([]Result{{Name: "up", Status: StatusPass}}))
+	assert.Equal(t, StatusWarn, StatusOf(policy([]Result{
+		{Name: "up", Status: StatusPass},
+		{Name: "down", Status: StatusFail},
+	})))
+}
@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by checks decorated with Breaker while their
+// circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of the circuit of a check decorated with
+// Breaker, as reported with its result.
+type BreakerState struct {
+	State string `json:"state"`
+	// Failures is the number of consecutive failures.
+	Failures int `json:"failures"`
+	// OpenedAt is when the circuit opened the last time.
+	OpenedAt time.Time `json:"openedAt,omitzero"`
+}
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+type breakerCheck struct {
+	check    Check
+	failures int
+	cooldown time.Duration
+
+	lock  sync.Mutex
+	state BreakerState
+	trial bool
+}
+
+// Breaker wraps a check of an expensive dependency with a circuit breaker.
+// After failures consecutive failures the circuit opens and the check fails
+// right away with ErrCircuitOpen, without running c. After cooldown the
+// circuit is half-open: the next run of c closes it again if c passes, and
+// reopens it otherwise.
+func Breaker(c Check, failures int, cooldown time.Duration) Check {
+	return &breakerCheck{
+		check:    c,
+		failures: max(failures, 1),
+		cooldown: cooldown,
+		state:    BreakerState{State: CircuitClosed},
+	}
+}
+
+func (b *breakerCheck) Pass() bool {
+	return b.Check(context.Background()) == nil
+}
+
+func (b *breakerCheck) Check(ctx context.Context) error {
+	b.lock.Lock()
+	if b.state.State == CircuitOpen && time.Since(b.state.OpenedAt) >= b.cooldown {
+		b.state.State = CircuitHalfOpen
+	}
+	if b.state.State == CircuitOpen || (b.state.State == CircuitHalfOpen && b.trial) {
+		state := b.state
+		b.lock.Unlock()
+
+		b.report(ctx, state)
+		return fmt.Errorf("%w since %s", ErrCircuitOpen, state.OpenedAt.Format(time.RFC3339))
+	}
+	b.trial = b.state.State == CircuitHalfOpen
+	b.lock.Unlock()
+
+	err := Run(ctx, b.check)
+
+	b.lock.Lock()
+	b.trial = false
+	if StatusOf(err) == StatusFail {
+		b.state.Failures++
+		if b.state.State == CircuitHalfOpen || b.state.Failures >= b.failures {
+			b.state.State = CircuitOpen
+			b.state.OpenedAt = time.Now().UTC()
+		}
+	} else {
+		b.state.State = CircuitClosed
+		b.state.Failures = 0
+	}
+	state := b.state
+	b.lock.Unlock()
+
+	b.report(ctx, state)
+	return err
+}
+
+func (b *breakerCheck) report(ctx context.Context, state BreakerState) {
+	collect(ctx, func(c *collector) {
+		c.breaker = &state
+	})
+}
+
+func (b *breakerCheck) Name() string {
+	return b.check.Name()
+}
+
+func (b *breakerCheck) Unwrap() Check {
+	return b.check
+}
@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	flaky := &flakyCheck{failures: 3}
+	check := Breaker(flaky, 2, 20*time.Millisecond)
+
+	result := RunCheck(context.Background(), check)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, &BreakerState{State: CircuitClosed, Failures: 1}, result.Breaker)
+
+	before := time.Now()
+	result = RunCheck(context.Background(), check)
+	assert.ErrorIs(t, result.Err, ErrCheckFailed)
+	if assert.NotNil(t, result.Breaker) {
+		assert.Equal(t, CircuitOpen, result.Breaker.State)
+		assert.Equal(t, 2, result.Breaker.Failures)
+		assert.False(t, result.Breaker.OpenedAt.Before(before.UTC()))
+	}
+
+	result = RunCheck(context.Background(), check)
+	assert.ErrorIs(t, result.Err, ErrCircuitOpen)
+	assert.Contains(t, result.Err.Error(), "circuit open since ")
+	assert.Equal(t, CircuitOpen, result.Breaker.State)
+	assert.Equal(t, int32(2), flaky.runs.Load(), "open circuits do not run the check")
+
+	// The trial run after the cooldown fails and reopens the circuit.
+	time.Sleep(20 * time.Millisecond)
+	result = RunCheck(context.Background(), check)
+	assert.ErrorIs(t, result.Err, ErrCheckFailed)
+	assert.Equal(t, CircuitOpen, result.Breaker.State)
+	assert.Equal(t, int32(3), flaky.runs.Load())
+	assert.ErrorIs(t, RunCheck(context.Background(), check).Err, ErrCircuitOpen)
+
+	// The next one passes and closes it.
+	time.Sleep(20 * time.Millisecond)
+	result = RunCheck(context.Background(), check)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, &BreakerState{State: CircuitClosed, Failures: 0, OpenedAt: result.Breaker.OpenedAt}, result.Breaker)
+	assert.True(t, check.Pass())
+}
+
+func TestBreakerHalfOpenRunsOneTrial(t *testing.T) {
+	slow := passOnlyCheck{pass: true, delay: 20 * time.Millisecond}
+	check := Breaker(slow, 1, 0).(*breakerCheck)
+	check.state = BreakerState{State: CircuitOpen, Failures: 1, OpenedAt: time.Now()}
+
+	trial := make(chan Result)
+	go func() {
+		trial <- RunCheck(context.Background(), check)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	result := RunCheck(context.Background(), check)
+	assert.ErrorIs(t, result.Err, ErrCircuitOpen)
+	assert.Equal(t, CircuitHalfOpen, result.Breaker.State)
+
+	result = <-trial
+	assert.NoError(t, result.Err)
+	assert.Equal(t, CircuitClosed, result.Breaker.State)
+}
+
+func TestBreakerKeepsOptions(t *testing.T) {
+	check := Breaker(Configure(resultCheck{name: "mongo"}, WithTags("db")), 1, time.Second)
+
+	assert.Equal(t, "mongo", check.Name())
+	assert.True(t, OptionsOf(check).HasTag("db"))
+}
@@ -1,6 +1,69 @@
 package checks
 
+import (
+	"context"
+	"errors"
+)
+
+// ErrCheckFailed is reported for checks that only implement Pass and fail.
+var ErrCheckFailed = errors.New("check failed")
+
 type Check interface {
 	Pass() bool
 	Name() string
 }
+
+// ContextCheck is implemented by checks that can be cancelled through ctx and
+// can report why they failed. All built-in checks implement it next to Check.
+type ContextCheck interface {
+	Check(ctx context.Context) error
+	Name() string
+}
+
+// AsContextCheck returns c as a ContextCheck. Checks that only implement Pass
+// are adapted: Pass runs in its own goroutine and the adapter returns the
+// context error as soon as ctx is done, even if Pass has not returned yet.
+func AsContextCheck(c Check) ContextCheck {
+	if cc, ok := c.(ContextCheck); ok {
+		return cc
+	}
+	return passCheck{c}
+}
+
+type passCheck struct {
+	check Check
+}
+
+func (p passCheck) Check(ctx context.Context) error {
+	if ctx.Done() == nil {
+		return p.run()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- newPanicError(v)
+			}
+		}()
+		done <- p.run()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p passCheck) run() error {
+	if !p.check.Pass() {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+func (p passCheck) Name() string {
+	return p.check.Name()
+}
@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type passOnlyCheck struct {
+	pass  bool
+	delay time.Duration
+}
+
+func (p passOnlyCheck) Pass() bool {
+	time.Sleep(p.delay)
+	return p.pass
+}
+
+func (p passOnlyCheck) Name() string {
+	return "pass only"
+}
+
+func TestAsContextCheckKeepsContextChecks(t *testing.T) {
+	check := NewEnvCheck("TEST_VAR")
+	assert.Equal(t, check, AsContextCheck(check))
+}
+
+func TestAsContextCheckAdaptsPass(t *testing.T) {
+	assert.NoError(t, AsContextCheck(passOnlyCheck{pass: true}).Check(context.Background()))
+	assert.ErrorIs(t, AsContextCheck(passOnlyCheck{pass: false}).Check(context.Background()), ErrCheckFailed)
+	assert.Equal(t, "pass only", AsContextCheck(passOnlyCheck{}).Name())
+}
+
+func TestAsContextCheckHonoursDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := AsContextCheck(passOnlyCheck{pass: true, delay: time.Second}).Check(ctx)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -49,6 +49,13 @@ func (c *contextCheck) Pass() bool {
 	return v == 0
 }
 
+func (c *contextCheck) Check(ctx context.Context) error {
+	if !c.Pass() {
+		return c.ctx.Err()
+	}
+	return nil
+}
+
 func (c *contextCheck) Name() string {
 	return c.name
 }
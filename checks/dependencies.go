@@ -0,0 +1,151 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDuplicateID       = errors.New("duplicate check ID")
+)
+
+// DependsOn makes a check depend on the checks with the given IDs or names.
+// The check is skipped instead of run when one of them failed.
+func DependsOn(checks ...string) Option {
+	return func(o *Options) {
+		o.DependsOn = append(o.DependsOn, checks...)
+	}
+}
+
+// Dependencies returns the indices of the checks in list every check of
+// list depends on. Dependencies that are not in list are left out. It
+// returns an error wrapping ErrDependencyCycle if the checks depend on each
+// other in a cycle.
+func Dependencies(list []Check) ([][]int, error) {
+	deps := make([][]int, len(list))
+	for idx, check := range list {
+		for _, ref := range OptionsOf(check).DependsOn {
+			if dep := Find(list, ref); dep >= 0 {
+				deps[idx] = append(deps[idx], dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(list))
+	var path []int
+
+	var visit func(idx int) error
+	visit = func(idx int) error {
+		switch state[idx] {
+		case visited:
+			return nil
+		case visiting:
+			var names []string
+			for i := slices.Index(path, idx); i < len(path); i++ {
+				names = append(names, list[path[i]].Name())
+			}
+			names = append(names, list[idx].Name())
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		}
+
+		state[idx] = visiting
+		path = append(path, idx)
+		for _, dep := range deps[idx] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[idx] = visited
+		return nil
+	}
+
+	for idx := range list {
+		if err := visit(idx); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// ValidateDependencies returns an error if a check of list depends on a
+// check that is not in list, or if the checks depend on each other in a
+// cycle.
+func ValidateDependencies(list []Check) error {
+	for _, check := range list {
+		for _, ref := range OptionsOf(check).DependsOn {
+			if Find(list, ref) < 0 {
+				return fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, check.Name(), ref)
+			}
+		}
+	}
+
+	_, err := Dependencies(list)
+	return err
+}
+
+// ValidateIDs returns an error if two checks of list were given the same ID
+// with WithID. IDs derived from names may clash, e.g. for two SqlChecks of
+// the same driver; see Matches for how such references are resolved.
+func ValidateIDs(list []Check) error {
+	seen := make(map[string]string, len(list))
+	for _, check := range list {
+		id := OptionsOf(check).ID
+		if id == "" {
+			continue
+		}
+		if other, ok := seen[id]; ok {
+			return fmt.Errorf("%w: %q and %q have the ID %q", ErrDuplicateID, other, check.Name(), id)
+		}
+		seen[id] = check.Name()
+	}
+	return nil
+}
+
+// Validate returns the error of ValidateIDs or ValidateDependencies.
+func Validate(list []Check) error {
+	if err := ValidateIDs(list); err != nil {
+		return err
+	}
+	return ValidateDependencies(list)
+}
+
+// Find returns the index of the first check in list with the ID or name
+// ref, or -1 if there is none. See Matches for the precedence.
+func Find(list []Check, ref string) int {
+	if matches := Matches(list, ref); len(matches) > 0 {
+		return matches[0]
+	}
+	return -1
+}
+
+// Matches returns the indices of the checks in list ref refers to. IDs set
+// with WithID take precedence over IDs derived from names, which take
+// precedence over names. More than one index means that ref is ambiguous.
+func Matches(list []Check, ref string) []int {
+	for _, match := range []func(Check) bool{
+		func(c Check) bool { return OptionsOf(c).ID == ref },
+		func(c Check) bool { return IDOf(c) == ref },
+		func(c Check) bool { return c.Name() == ref },
+	} {
+		var matches []int
+		for idx, check := range list {
+			if match(check) {
+				matches = append(matches, idx)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	list := []Check{
+		Configure(resultCheck{name: "mongo"}, DependsOn("gateway")),
+		Configure(resultCheck{name: "gateway"}, WithID("gw")),
+		Configure(resultCheck{name: "redis"}, DependsOn("gw", "other")),
+	}
+
+	deps, err := Dependencies(list)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1}, nil, {1}}, deps)
+
+	assert.ErrorIs(t, ValidateDependencies(list), ErrUnknownDependency)
+	assert.EqualError(t, ValidateDependencies(list), `unknown dependency: "redis" depends on "other"`)
+	assert.NoError(t, ValidateDependencies(list[:2]))
+}
+
+func TestValidateIDs(t *testing.T) {
+	list := []Check{
+		Configure(resultCheck{name: "Main DB"}, WithID("db")),
+		Configure(resultCheck{name: "replica"}, WithID("db")),
+		resultCheck{name: "redis"},
+		resultCheck{name: "redis"},
+	}
+
+	assert.ErrorIs(t, Validate(list), ErrDuplicateID)
+	assert.EqualError(t, ValidateIDs(list), `duplicate check ID: "Main DB" and "replica" have the ID "db"`)
+	assert.NoError(t, Validate(list[1:]), "derived IDs may clash")
+}
+
+func TestMatches(t *testing.T) {
+	list := []Check{
+		resultCheck{name: "redis"},
+		resultCheck{name: "redis"},
+		Configure(resultCheck{name: "cache"}, WithID("Redis")),
+	}
+
+	assert.Equal(t, []int{2}, Matches(list, "Redis"))
+	assert.Equal(t, []int{0, 1}, Matches(list, "redis"))
+	assert.Equal(t, 0, Find(list, "redis"))
+	assert.Empty(t, Matches(list, "mongo"))
+}
+
+func TestDependencyCycle(t *testing.T) {
+	list := []Check{
+		Configure(resultCheck{name: "a"}, DependsOn("b")),
+		Configure(resultCheck{name: "b"}, DependsOn("c")),
+		Configure(resultCheck{name: "c"}, DependsOn("a")),
+	}
+
+	_, err := Dependencies(list)
+	assert.True(t, errors.Is(err, ErrDependencyCycle))
+	assert.EqualError(t, err, "dependency cycle: a -> b -> c -> a")
+	assert.ErrorIs(t, ValidateDependencies(list), ErrDependencyCycle)
+
+	_, err = Dependencies([]Check{Configure(resultCheck{name: "self"}, DependsOn("self"))})
+	assert.EqualError(t, err, "dependency cycle: self -> self")
+}
+
+func TestFind(t *testing.T) {
+	list := []Check{resultCheck{name: "gw/1"}, Configure(resultCheck{name: "Gateway API"}, WithID("gw/1"))}
+
+	assert.Equal(t, 1, Find(list, "gw/1"), "IDs take precedence over names")
+	assert.Equal(t, 0, Find(list, "gw-1"))
+	assert.Equal(t, 1, Find(list, "Gateway API"))
+	assert.Equal(t, -1, Find(list, "gateway-api"))
+	assert.Equal(t, -1, Find(list, "redis"))
+}
@@ -1,6 +1,8 @@
 package checks
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"regexp"
 )
@@ -22,15 +24,24 @@ func (e EnvCheck) SetRegexValidator(Regex string) {
 }
 
 func (e EnvCheck) Pass() bool {
+	return e.Check(context.Background()) == nil
+}
+
+func (e EnvCheck) Check(ctx context.Context) error {
 	envValue := os.Getenv(e.EnvVariable)
 	if envValue == "" {
-		return false
+		return fmt.Errorf("environmental variable %q is not set", e.EnvVariable)
 	}
 	if e.Regex != "" {
-		matched, _ := regexp.MatchString(e.Regex, envValue)
-		return matched
+		matched, err := regexp.MatchString(e.Regex, envValue)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return fmt.Errorf("environmental variable %q does not match %q", e.EnvVariable, e.Regex)
+		}
 	}
-	return true
+	return nil
 }
 
 func (e EnvCheck) Name() string {
@@ -1,6 +1,7 @@
 package checks
 
 import (
+	"context"
 	"os"
 	"testing"
 )
@@ -54,3 +55,17 @@ func TestEnvCheck_Pass(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvCheck_Check(t *testing.T) {
+	os.Setenv("TEST_VAR", "12345")
+	defer os.Unsetenv("TEST_VAR")
+
+	if err := (EnvCheck{EnvVariable: "NOT_EXIST"}).Check(context.Background()); err == nil ||
+		err.Error() != `environmental variable "NOT_EXIST" is not set` {
+		t.Errorf("EnvCheck.Check() = %v, want not set error", err)
+	}
+
+	if err := (EnvCheck{EnvVariable: "TEST_VAR", Regex: "["}).Check(context.Background()); err == nil {
+		t.Errorf("EnvCheck.Check() with invalid regex = nil, want error")
+	}
+}
@@ -2,6 +2,7 @@ package checks
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -20,20 +21,31 @@ func NewInfluxV2Check(timeout int, client influxdb2.Client) *InfluxV2Check {
 }
 
 func (i *InfluxV2Check) Pass() bool {
+	return i.Check(context.Background()) == nil
+}
+
+// Check pings InfluxDB. A positive Timeout (in seconds) is applied on top of
+// any deadline ctx already carries.
+func (i *InfluxV2Check) Check(ctx context.Context) error {
 	if i.client == nil {
-		return false
+		return errors.New("no influxdb client configured")
 	}
 
-	timeout := time.Second * time.Duration(i.Timeout)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(i.Timeout))
+		defer cancel()
+	}
 
 	ping, err := i.client.Ping(ctx)
 	if err != nil {
-		return false
+		return err
+	}
+	if !ping {
+		return errors.New("influxdb is not ready")
 	}
 
-	return ping
+	return nil
 }
 
 func (i *InfluxV2Check) Name() string {
@@ -31,18 +31,16 @@ func TestInfluxCheck_Pass(t *testing.T) {
 }
 
 func TestInfluxCheck_Timeout(t *testing.T) {
-	http.HandleFunc("/health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(2 * time.Second)
-	}))
-
-	go func() {
-		if err := http.ListenAndServe(":8080", nil); err != nil {
-			t.Errorf("server.ListenAndServe() error = %v", err)
-			return
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
 		}
-	}()
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
 
-	influxClient := influxdb2.NewClient("localhost:8080", "")
+	influxClient := influxdb2.NewClient(server.URL, "")
 	check := NewInfluxV2Check(1, influxClient)
 	if check.Pass() {
 		t.Error("InfluxCheck.Pass() returned true, want false")
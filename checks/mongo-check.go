@@ -2,6 +2,7 @@ package checks
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -20,20 +21,23 @@ func NewMongoCheck(timeout int, client *mongo.Client) *MongoCheck {
 }
 
 func (m *MongoCheck) Pass() bool {
+	return m.Check(context.Background()) == nil
+}
+
+// Check pings MongoDB. A positive Timeout (in seconds) is applied on top of
+// any deadline ctx already carries.
+func (m *MongoCheck) Check(ctx context.Context) error {
 	if m.client == nil {
-		return false
+		return errors.New("no mongodb client configured")
 	}
 
-	timeout := time.Second * time.Duration(m.Timeout)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	err := m.client.Ping(ctx, nil)
-	if err != nil {
-		return false
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(m.Timeout))
+		defer cancel()
 	}
 
-	return true
+	return m.client.Ping(ctx, nil)
 }
 
 func (m *MongoCheck) Name() string {
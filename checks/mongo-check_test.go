@@ -1,21 +1,23 @@
 package checks
 
 import (
-	"fmt"
+	"context"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
 func TestMongoCheck_Pass(t *testing.T) {
-	mt := mtest.New(t, mtest.NewOptions().CreateClient(true).ClientType(mtest.Mock))
-	defer mt.Close()
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 
-	fmt.Println(mt.Client)
-	check := NewMongoCheck(2, mt.Client)
-	if !check.Pass() {
-		t.Errorf("Expected MongoCheck.Pass to return true, got false")
-	}
+	mt.Run("ping succeeds", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		check := NewMongoCheck(2, mt.Client)
+		if !check.Pass() {
+			t.Errorf("Expected MongoCheck.Pass to return true, got false")
+		}
+	})
 }
 
 func TestMongoCheck_Name(t *testing.T) {
@@ -26,18 +28,39 @@ func TestMongoCheck_Name(t *testing.T) {
 }
 
 func TestMongoCheck_Fail(t *testing.T) {
-	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
-	mt.Close()
-
 	// Client not set
 	check := NewMongoCheck(2, nil)
 	if check.Pass() {
 		t.Errorf("Expected MongoCheck.Pass to return false, got true")
 	}
 
-	// Connection closed
-	check = NewMongoCheck(2, mt.Client)
-	if check.Pass() {
-		t.Errorf("Expected MongoCheck.Pass to return false, got true")
-	}
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("ping fails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    13,
+			Message: "unauthorized",
+		}))
+
+		check := NewMongoCheck(2, mt.Client)
+		if check.Pass() {
+			t.Errorf("Expected MongoCheck.Pass to return false, got true")
+		}
+	})
+}
+
+func TestMongoCheck_CheckHonoursContext(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("context cancelled", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		check := NewMongoCheck(2, mt.Client)
+		if err := check.Check(ctx); err == nil {
+			t.Errorf("Expected MongoCheck.Check to return an error for a cancelled context")
+		}
+	})
 }
@@ -0,0 +1,217 @@
+package checks
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Probe is a kind of Kubernetes probe a check can belong to.
+type Probe string
+
+const (
+	Liveness  Probe = "liveness"
+	Readiness Probe = "readiness"
+	Startup   Probe = "startup"
+)
+
+// Options control how a check is evaluated by the health endpoints.
+type Options struct {
+	// Probes the check belongs to. Checks without probes belong to the
+	// readiness probe only.
+	Probes []Probe
+	// Interval between two runs of the check in the background scheduler.
+	// Zero uses the scheduler's default interval.
+	Interval time.Duration
+	// ComponentType of the check in application/health+json responses,
+	// e.g. "datastore", "component" or "system".
+	ComponentType string
+	// Tags the health endpoint can filter checks by.
+	Tags []string
+	// ID identifies the check in URLs. It defaults to the slug of its name.
+	ID string
+	// Optional checks are reported, but do not affect the aggregated status,
+	// the HTTP status code and failure notifications.
+	Optional bool
+	// DependsOn are the IDs or names of the checks the check depends on.
+	DependsOn []string
+	// FailureThreshold is the number of consecutive failures after which
+	// the check is reported as failed, SuccessThreshold the number of
+	// consecutive successes after which a failed check recovers. Zero means
+	// one.
+	FailureThreshold int
+	SuccessThreshold int
+	// Deep checks are expensive and only evaluated on request.
+	Deep bool
+}
+
+// HasProbe reports whether the check belongs to probe.
+func (o Options) HasProbe(probe Probe) bool {
+	if len(o.Probes) == 0 {
+		return probe == Readiness
+	}
+	for _, p := range o.Probes {
+		if p == probe {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag reports whether the check is tagged with tag.
+func (o Options) HasTag(tag string) bool {
+	for _, t := range o.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type Option func(*Options)
+
+// WithProbes assigns a check to the given probes.
+func WithProbes(probes ...Probe) Option {
+	return func(o *Options) {
+		o.Probes = append(o.Probes, probes...)
+	}
+}
+
+// WithInterval sets how often the background scheduler runs a check.
+func WithInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = interval
+	}
+}
+
+// WithComponentType sets the componentType reported for a check in
+// application/health+json responses.
+func WithComponentType(componentType string) Option {
+	return func(o *Options) {
+		o.ComponentType = componentType
+	}
+}
+
+// WithTags tags a check, e.g. with "db", "cache" or "external".
+func WithTags(tags ...string) Option {
+	return func(o *Options) {
+		o.Tags = append(o.Tags, tags...)
+	}
+}
+
+// WithID sets a stable, URL-safe ID for a check.
+func WithID(id string) Option {
+	return func(o *Options) {
+		o.ID = id
+	}
+}
+
+// Optional marks a check as optional. Checks are critical by default.
+func Optional() Option {
+	return func(o *Options) {
+		o.Optional = true
+	}
+}
+
+// Deep marks a check as deep: the health endpoints only evaluate it when
+// asked to with the deep=true query parameter.
+func Deep() Option {
+	return func(o *Options) {
+		o.Deep = true
+	}
+}
+
+// WithThresholds smooths flapping checks: a check is reported as failed
+// only after failures consecutive failures, and as recovered only after
+// successes consecutive successes.
+func WithThresholds(failures, successes int) Option {
+	return func(o *Options) {
+		o.FailureThreshold = failures
+		o.SuccessThreshold = successes
+	}
+}
+
+// IDOf returns the ID of c: the one set with WithID, or the slug of its name.
+func IDOf(c Check) string {
+	if id := OptionsOf(c).ID; id != "" {
+		return id
+	}
+	return Slug(c.Name())
+}
+
+// Slug turns name into a URL-safe ID by lower casing it and replacing
+// everything but letters and digits with dashes, e.g.
+// `Environmental variable "DB_HOST"` becomes "environmental-variable-db-host".
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+type configuredCheck struct {
+	check Check
+	opts  []Option
+}
+
+// Configure attaches opts to c. Options of a check that is configured more
+// than once are combined.
+func Configure(c Check, opts ...Option) Check {
+	if cfg, ok := c.(*configuredCheck); ok {
+		return &configuredCheck{
+			check: cfg.check,
+			opts:  append(append([]Option{}, cfg.opts...), opts...),
+		}
+	}
+	return &configuredCheck{check: c, opts: opts}
+}
+
+// OptionsOf returns the options attached to c with Configure, including the
+// ones of checks wrapped by c.
+func OptionsOf(c Check) Options {
+	var layers [][]Option
+	for c != nil {
+		if cfg, ok := c.(*configuredCheck); ok {
+			layers = append(layers, cfg.opts)
+		}
+		u, ok := c.(interface{ Unwrap() Check })
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+
+	var options Options
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, opt := range layers[i] {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+func (c *configuredCheck) Pass() bool {
+	return c.check.Pass()
+}
+
+func (c *configuredCheck) Check(ctx context.Context) error {
+	return AsContextCheck(c.check).Check(ctx)
+}
+
+func (c *configuredCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *configuredCheck) Unwrap() Check {
+	return c.check
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsOfUnconfiguredCheck(t *testing.T) {
+	options := OptionsOf(passOnlyCheck{})
+
+	assert.Empty(t, options.Probes)
+	assert.True(t, options.HasProbe(Readiness))
+	assert.False(t, options.HasProbe(Liveness))
+	assert.False(t, options.HasProbe(Startup))
+}
+
+func TestConfigure(t *testing.T) {
+	check := Configure(passOnlyCheck{pass: true}, WithProbes(Liveness))
+
+	assert.Equal(t, "pass only", check.Name())
+	assert.True(t, check.Pass())
+	assert.NoError(t, AsContextCheck(check).Check(context.Background()))
+
+	options := OptionsOf(check)
+	assert.Equal(t, []Probe{Liveness}, options.Probes)
+	assert.True(t, options.HasProbe(Liveness))
+	assert.False(t, options.HasProbe(Readiness))
+}
+
+func TestConfigureCombinesOptions(t *testing.T) {
+	check := Configure(Configure(passOnlyCheck{}, WithProbes(Liveness)), WithProbes(Startup))
+
+	assert.Equal(t, []Probe{Liveness, Startup}, OptionsOf(check).Probes)
+}
+
+func TestOptionsOfLooksThroughWrappers(t *testing.T) {
+	check := Soft(Configure(passOnlyCheck{}, WithProbes(Startup)))
+
+	assert.Equal(t, []Probe{Startup}, OptionsOf(check).Probes)
+}
+
+func TestWithComponentType(t *testing.T) {
+	check := Configure(passOnlyCheck{}, WithComponentType("datastore"))
+
+	assert.Equal(t, "datastore", OptionsOf(check).ComponentType)
+}
+
+func TestWithTags(t *testing.T) {
+	check := Configure(Configure(passOnlyCheck{}, WithTags("db")), WithTags("external"))
+
+	options := OptionsOf(check)
+	assert.Equal(t, []string{"db", "external"}, options.Tags)
+	assert.True(t, options.HasTag("db"))
+	assert.False(t, options.HasTag("cache"))
+}
+
+func TestOptional(t *testing.T) {
+	assert.False(t, OptionsOf(passOnlyCheck{}).Optional)
+	assert.True(t, OptionsOf(Configure(passOnlyCheck{}, Optional())).Optional)
+}
+
+func TestWithThresholds(t *testing.T) {
+	options := OptionsOf(Configure(passOnlyCheck{}, WithThresholds(3, 2)))
+
+	assert.Equal(t, 3, options.FailureThreshold)
+	assert.Equal(t, 2, options.SuccessThreshold)
+}
+
+func TestDeep(t *testing.T) {
+	assert.False(t, OptionsOf(passOnlyCheck{}).Deep)
+	assert.True(t, OptionsOf(Configure(passOnlyCheck{}, Deep())).Deep)
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"redis":                               "redis",
+		`Environmental variable "DB_HOST"`:    "environmental-variable-db-host",
+		"ping-https://example.com/health?x=1": "ping-https-example-com-health-x-1",
+		"*sqlmock.mockDriver":                 "sqlmock-mockdriver",
+		"  --  ":                              "",
+	}
+
+	for name, want := range tests {
+		assert.Equal(t, want, Slug(name), name)
+	}
+}
+
+func TestIDOf(t *testing.T) {
+	assert.Equal(t, "environmental-variable-db-host", IDOf(NewEnvCheck("DB_HOST")))
+	assert.Equal(t, "database", IDOf(Configure(NewEnvCheck("DB_HOST"), WithID("database"))))
+}
@@ -0,0 +1,59 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// stackFrames is the number of frames kept in the stack of a PanicError.
+const stackFrames = 10
+
+// PanicError is the error of a check that panicked.
+type PanicError struct {
+	Value any
+	// Stack is the stack of the panicking goroutine, starting at the frame
+	// that panicked.
+	Stack string
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Run runs c as a ContextCheck and returns a *PanicError if it panics.
+func Run(ctx context.Context, c Check) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = newPanicError(v)
+		}
+	}()
+	return AsContextCheck(c).Check(ctx)
+}
+
+func newPanicError(v any) *PanicError {
+	return &PanicError{Value: v, Stack: trimStack(string(debug.Stack()))}
+}
+
+// trimStack removes the frames of the runtime and the recovering function
+// from stack, and keeps at most stackFrames frames of the panicking code.
+func trimStack(stack string) string {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+
+	// Every frame takes two lines: the function and its file. The
+	// panicking code follows the frame of panic.
+	start := 1
+	for i := 1; i+1 < len(lines); i += 2 {
+		if strings.HasPrefix(lines[i], "panic(") {
+			start = i + 2
+			break
+		}
+	}
+
+	end := min(start+2*stackFrames, len(lines))
+	if start >= end {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
@@ -0,0 +1,51 @@
+package checks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panicCheck struct{}
+
+func (p panicCheck) Pass() bool {
+	var m map[string]int
+	m["boom"]++
+	return true
+}
+
+func (p panicCheck) Name() string {
+	return "panic"
+}
+
+func TestRunRecoversPanics(t *testing.T) {
+	err := Run(context.Background(), panicCheck{})
+
+	var panicErr *PanicError
+	if assert.ErrorAs(t, err, &panicErr) {
+		assert.Equal(t, "panic: assignment to entry in nil map", err.Error())
+		assert.True(t, strings.HasPrefix(panicErr.Stack, "github.com/tavsec/gin-healthcheck/v2/checks.panicCheck.Pass("), panicErr.Stack)
+		assert.LessOrEqual(t, strings.Count(panicErr.Stack, "\n"), 2*stackFrames-1)
+	}
+}
+
+func TestRunRecoversPanicsOfPassGoroutine(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, Run(ctx, panicCheck{}), &panicErr)
+}
+
+func TestAggregateRecoversPanics(t *testing.T) {
+	result := RunCheck(context.Background(), AllOf("all", up, panicCheck{}))
+
+	assert.Equal(t, StatusFail, result.Status)
+	var panicErr *PanicError
+	if assert.Len(t, result.Children, 2) {
+		assert.ErrorAs(t, result.Children[1].Err, &panicErr)
+	}
+}
@@ -1,6 +1,8 @@
 package checks
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -39,10 +41,13 @@ func NewPingCheck(URL, Method string, Timeout int, Body io.Reader, Headers map[s
 }
 
 func (p PingCheck) Pass() bool {
-	req, err := http.NewRequest(p.Method, p.URL, p.Body)
+	return p.Check(context.Background()) == nil
+}
 
+func (p PingCheck) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, p.Method, p.URL, p.Body)
 	if err != nil {
-		return false
+		return err
 	}
 
 	for key, value := range p.Headers {
@@ -50,10 +55,13 @@ func (p PingCheck) Pass() bool {
 	}
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return false
+		return err
 	}
 	resp.Body.Close()
-	return resp.StatusCode <= 299
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
 }
 
 func (p PingCheck) Name() string {
@@ -2,6 +2,8 @@ package checks
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -100,18 +102,16 @@ func TestPingCheck_Pass_InvalidRequest(t *testing.T) {
 }
 
 func TestPingCheck_Pass_Timeout(t *testing.T) {
-	http.HandleFunc("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(5 * time.Second)
-	}))
-
-	go func() {
-		if err := http.ListenAndServe(":8080", nil); err != nil {
-			t.Errorf("server.ListenAndServe() error = %v", err)
-			return
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
 		}
-	}()
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
 
-	url := "http://localhost:8080/test"
+	url := server.URL + "/test"
 	method := "GET"
 	check := NewPingCheck(url, method, 1000, nil, nil)
 
@@ -166,3 +166,38 @@ func TestNewPingCheck_NoHeaders(t *testing.T) {
 		t.Errorf("NewPingCheck() Headers = %v, want nil", check.Headers)
 	}
 }
+
+func TestPingCheck_Check_ReportsStatusCode(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	check := NewPingCheck(server.URL, "GET", 1000, nil, nil)
+
+	err := check.Check(context.Background())
+	if err == nil || err.Error() != "unexpected status code 502" {
+		t.Errorf("PingCheck.Check() = %v, want unexpected status code 502", err)
+	}
+}
+
+func TestPingCheck_Check_ContextDeadline(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	check := NewPingCheck(server.URL, "GET", 5000, nil, nil)
+
+	if err := check.Check(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PingCheck.Check() = %v, want context.DeadlineExceeded", err)
+	}
+}
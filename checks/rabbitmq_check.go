@@ -1,6 +1,11 @@
 package checks
 
-import amqp "github.com/rabbitmq/amqp091-go"
+import (
+	"context"
+	"errors"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
 
 type RabbitMQCheck struct {
 	conn *amqp.Connection
@@ -13,11 +18,18 @@ func NewRabbitCheck(conn *amqp.Connection) *RabbitMQCheck {
 }
 
 func (r *RabbitMQCheck) Pass() bool {
+	return r.Check(context.Background()) == nil
+}
+
+func (r *RabbitMQCheck) Check(ctx context.Context) error {
 	if r.conn == nil {
-		return false
+		return errors.New("no rabbitmq connection configured")
+	}
+	if r.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
 	}
 
-	return !r.conn.IsClosed()
+	return nil
 }
 
 func (r *RabbitMQCheck) Name() string {
@@ -19,8 +19,11 @@ func (r *RedisCheck) Pass() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err := r.client.Ping(ctx).Result()
-	return err == nil
+	return r.Check(ctx) == nil
+}
+
+func (r *RedisCheck) Check(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
 }
 
 func (r *RedisCheck) Name() string {
@@ -1,6 +1,7 @@
 package checks
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -42,3 +43,12 @@ func TestRedisCheck_Name(t *testing.T) {
 	assert.Equal(t, "redis", redisCheck.Name())
 
 }
+
+func TestRedisCheck_CheckReturnsError(t *testing.T) {
+	mockClient, mock := redismock.NewClientMock()
+	mock.ExpectPing().SetErr(errors.New("ping failed"))
+
+	redisCheck := NewRedisCheck(mockClient)
+
+	assert.EqualError(t, redisCheck.Check(context.Background()), "ping failed")
+}
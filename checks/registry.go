@@ -0,0 +1,99 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrDuplicateCheck = errors.New("check is already registered")
+	ErrCheckNotFound  = errors.New("check is not registered")
+)
+
+// Registry is a set of checks, identified by their names, that can be
+// changed while the health endpoint is serving requests.
+type Registry struct {
+	lock   sync.RWMutex
+	checks []Check
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds c, unless a check with the same name or the same ID set
+// with WithID is registered, or c would close a dependency cycle. Dependencies on checks that are not
+// registered are ignored until they are.
+func (r *Registry) Register(c Check) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.index(c.Name()) >= 0 {
+		return fmt.Errorf("%w: %q", ErrDuplicateCheck, c.Name())
+	}
+	list := append(append([]Check(nil), r.checks...), c)
+	if err := ValidateIDs(list); err != nil {
+		return err
+	}
+	if _, err := Dependencies(list); err != nil {
+		return err
+	}
+	r.checks = list
+	return nil
+}
+
+// Unregister removes the check named name.
+func (r *Registry) Unregister(name string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	idx := r.index(name)
+	if idx < 0 {
+		return fmt.Errorf("%w: %q", ErrCheckNotFound, name)
+	}
+	r.checks = append(r.checks[:idx], r.checks[idx+1:]...)
+	return nil
+}
+
+// Replace swaps the check named name for c, keeping its position.
+func (r *Registry) Replace(name string, c Check) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	idx := r.index(name)
+	if idx < 0 {
+		return fmt.Errorf("%w: %q", ErrCheckNotFound, name)
+	}
+	if other := r.index(c.Name()); other >= 0 && other != idx {
+		return fmt.Errorf("%w: %q", ErrDuplicateCheck, c.Name())
+	}
+
+	list := append([]Check(nil), r.checks...)
+	list[idx] = c
+	if err := ValidateIDs(list); err != nil {
+		return err
+	}
+	if _, err := Dependencies(list); err != nil {
+		return err
+	}
+	r.checks = list
+	return nil
+}
+
+// List returns the registered checks in registration order.
+func (r *Registry) List() []Check {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]Check(nil), r.checks...)
+}
+
+func (r *Registry) index(name string) int {
+	for idx, c := range r.checks {
+		if c.Name() == name {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	assert.Empty(t, r.List())
+
+	first := NewEnvCheck("FIRST")
+	second := NewEnvCheck("SECOND")
+	assert.NoError(t, r.Register(first))
+	assert.NoError(t, r.Register(second))
+	assert.ErrorIs(t, r.Register(NewEnvCheck("FIRST")), ErrDuplicateCheck)
+	assert.Equal(t, []Check{first, second}, r.List())
+
+	replacement := Soft(NewEnvCheck("FIRST"))
+	assert.NoError(t, r.Replace(first.Name(), replacement))
+	assert.Equal(t, []Check{replacement, second}, r.List())
+	assert.ErrorIs(t, r.Replace("unknown", first), ErrCheckNotFound)
+	assert.ErrorIs(t, r.Replace(first.Name(), second), ErrDuplicateCheck)
+
+	assert.NoError(t, r.Unregister(first.Name()))
+	assert.Equal(t, []Check{second}, r.List())
+	assert.ErrorIs(t, r.Unregister(first.Name()), ErrCheckNotFound)
+}
+
+func TestRegistryRejectsDuplicateIDs(t *testing.T) {
+	r := NewRegistry()
+	assert.NoError(t, r.Register(Configure(NewEnvCheck("FIRST"), WithID("env"))))
+	assert.NoError(t, r.Register(NewEnvCheck("SECOND")))
+
+	assert.ErrorIs(t, r.Register(Configure(NewEnvCheck("THIRD"), WithID("env"))), ErrDuplicateID)
+	assert.ErrorIs(t, r.Replace(NewEnvCheck("SECOND").Name(), Configure(NewEnvCheck("THIRD"), WithID("env"))), ErrDuplicateID)
+	assert.Len(t, r.List(), 2)
+}
+
+func TestRegistryListIsASnapshot(t *testing.T) {
+	r := NewRegistry()
+	assert.NoError(t, r.Register(NewEnvCheck("FIRST")))
+	assert.NoError(t, r.Register(NewEnvCheck("SECOND")))
+
+	list := r.List()
+	assert.NoError(t, r.Unregister(NewEnvCheck("FIRST").Name()))
+	assert.NoError(t, r.Register(NewEnvCheck("THIRD")))
+
+	assert.Equal(t, NewEnvCheck("FIRST"), list[0])
+	assert.Equal(t, NewEnvCheck("SECOND"), list[1])
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			check := NewEnvCheck(fmt.Sprint("VAR_", i))
+			assert.NoError(t, r.Register(check))
+			assert.NoError(t, r.Unregister(check.Name()))
+		}(i)
+		go func() {
+			defer wg.Done()
+			r.List()
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, r.List())
+}
+
+func TestRegistryRejectsDependencyCycles(t *testing.T) {
+	r := NewRegistry()
+	a := Configure(resultCheck{name: "a"}, DependsOn("b"))
+	b := resultCheck{name: "b"}
+
+	assert.NoError(t, r.Register(a))
+	assert.NoError(t, r.Register(b))
+	assert.ErrorIs(t, r.Replace("b", Configure(b, DependsOn("a"))), ErrDependencyCycle)
+	assert.Equal(t, []Check{a, b}, r.List())
+
+	assert.NoError(t, r.Unregister("b"))
+	assert.ErrorIs(t, r.Register(Configure(b, DependsOn("a"), DependsOn("b"))), ErrDependencyCycle)
+	assert.Equal(t, []Check{a}, r.List())
+}
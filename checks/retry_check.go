@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+type retryCheck struct {
+	check    Check
+	attempts int
+	backoff  time.Duration
+}
+
+// Retry wraps a check of a dependency with transient failures: a failed run
+// is retried up to attempts runs in total. The delay before the n-th retry
+// is backoff doubled n-1 times, reduced by a random jitter of up to half of
+// it. Retries stop when the next delay would exceed the deadline of the
+// check. The number of runs is reported in the result of the check.
+func Retry(c Check, attempts int, backoff time.Duration) Check {
+	return retryCheck{check: c, attempts: max(attempts, 1), backoff: backoff}
+}
+
+func (r retryCheck) Pass() bool {
+	return r.Check(context.Background()) == nil
+}
+
+func (r retryCheck) Check(ctx context.Context) error {
+	delay := r.backoff
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = Run(ctx, r.check)
+		if StatusOf(err) != StatusFail || attempt == r.attempts {
+			break
+		}
+
+		wait := delay
+		if wait > 1 {
+			wait -= rand.N(wait / 2)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		delay *= 2
+	}
+
+	collect(ctx, func(c *collector) {
+		c.attempts = attempt
+	})
+	return err
+}
+
+func (r retryCheck) Name() string {
+	return r.check.Name()
+}
+
+func (r retryCheck) Unwrap() Check {
+	return r.check
+}
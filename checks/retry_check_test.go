@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyCheck fails its first failures runs.
+type flakyCheck struct {
+	failures int32
+	runs     atomic.Int32
+}
+
+func (f *flakyCheck) Pass() bool {
+	return f.runs.Add(1) > f.failures
+}
+
+func (f *flakyCheck) Name() string {
+	return "flaky"
+}
+
+func TestRetry(t *testing.T) {
+	flaky := &flakyCheck{failures: 2}
+	check := Retry(flaky, 3, time.Millisecond)
+
+	result := RunCheck(context.Background(), check)
+
+	assert.Equal(t, "flaky", result.Name)
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, int32(3), flaky.runs.Load())
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	flaky := &flakyCheck{failures: 5}
+
+	result := RunCheck(context.Background(), Retry(flaky, 3, time.Millisecond))
+
+	assert.Equal(t, StatusFail, result.Status)
+	assert.ErrorIs(t, result.Err, ErrCheckFailed)
+	assert.Equal(t, 3, result.Attempts)
+	assert.False(t, Retry(&flakyCheck{failures: 5}, 2, 0).Pass())
+}
+
+func TestRetryDoesNotRetryWarnings(t *testing.T) {
+	result := RunCheck(context.Background(), Retry(degraded, 3, time.Millisecond))
+
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, 1, result.Attempts)
+}
+
+func TestRetryStaysWithinDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := RunCheck(ctx, Retry(resultCheck{name: "down", err: errors.New("down")}, 10, 20*time.Millisecond))
+
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.EqualError(t, result.Err, "down")
+	assert.GreaterOrEqual(t, result.Attempts, 2)
+	assert.Less(t, result.Attempts, 10)
+}
+
+func TestRetryKeepsOptions(t *testing.T) {
+	check := Retry(Configure(resultCheck{name: "up"}, WithTags("db")), 3, 0)
+
+	assert.Equal(t, "up", check.Name())
+	assert.True(t, OptionsOf(check).HasTag("db"))
+}
@@ -0,0 +1,29 @@
+package checks
+
+import "context"
+
+type softCheck struct {
+	check Check
+}
+
+// Soft wraps a check of a soft dependency: its failures mark the service as
+// degraded (StatusWarn) instead of failing it.
+func Soft(c Check) Check {
+	return softCheck{check: c}
+}
+
+func (s softCheck) Pass() bool {
+	return true
+}
+
+func (s softCheck) Check(ctx context.Context) error {
+	return Warn(AsContextCheck(s.check).Check(ctx))
+}
+
+func (s softCheck) Name() string {
+	return s.check.Name()
+}
+
+func (s softCheck) Unwrap() Check {
+	return s.check
+}
@@ -1,7 +1,9 @@
 package checks
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"reflect"
 )
 
@@ -10,13 +12,15 @@ type SqlCheck struct {
 }
 
 func (s SqlCheck) Pass() bool {
+	return s.Check(context.Background()) == nil
+}
+
+func (s SqlCheck) Check(ctx context.Context) error {
 	if s.Sql == nil {
-		return false
+		return errors.New("no database configured")
 	}
 
-	err := s.Sql.Ping()
-
-	return err == nil
+	return s.Sql.PingContext(ctx)
 }
 
 func (s SqlCheck) Name() string {
@@ -1,8 +1,11 @@
 package checks
 
 import (
-	"github.com/DATA-DOG/go-sqlmock"
+	"context"
+	"database/sql/driver"
 	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
 )
 
 func TestSqlCheck_Pass(t *testing.T) {
@@ -15,7 +18,7 @@ func TestSqlCheck_Pass(t *testing.T) {
 	check := SqlCheck{Sql: db}
 
 	if check.Name() != "*sqlmock.mockDriver" {
-		t.Errorf("Expected SqlCheck.Name to return '*sqlmock.mockDriver', got '" + check.Name() + "'")
+		t.Errorf("Expected SqlCheck.Name to return '*sqlmock.mockDriver', got '%s'", check.Name())
 	}
 
 	if !check.Pass() {
@@ -50,3 +53,18 @@ func TestSqlCheck_Fail(t *testing.T) {
 	}
 
 }
+
+func TestSqlCheck_CheckReturnsPingError(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing().WillReturnError(driver.ErrBadConn)
+
+	check := SqlCheck{Sql: db}
+	if err := check.Check(context.Background()); err == nil {
+		t.Errorf("Expected SqlCheck.Check to return an error, got nil")
+	}
+}
@@ -0,0 +1,65 @@
+package checks
+
+import "errors"
+
+// Status is the tri-state result of a check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Worse returns the more severe of s and other.
+func (s Status) Worse(other Status) Status {
+	if s.severity() >= other.severity() {
+		return s
+	}
+	return other
+}
+
+func (s Status) severity() int {
+	switch s {
+	case StatusPass:
+		return 0
+	case StatusWarn:
+		return 1
+	default:
+		return 2
+	}
+}
+
+type warnError struct {
+	err error
+}
+
+func (w *warnError) Error() string {
+	return w.err.Error()
+}
+
+func (w *warnError) Unwrap() error {
+	return w.err
+}
+
+// Warn marks err as a degraded result. A ContextCheck returning it is
+// reported as StatusWarn instead of StatusFail.
+func Warn(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &warnError{err: err}
+}
+
+// StatusOf maps the error returned by a ContextCheck to its Status.
+func StatusOf(err error) Status {
+	if err == nil {
+		return StatusPass
+	}
+
+	var warn *warnError
+	if errors.As(err, &warn) {
+		return StatusWarn
+	}
+	return StatusFail
+}
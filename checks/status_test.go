@@ -0,0 +1,42 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusOf(t *testing.T) {
+	assert.Equal(t, StatusPass, StatusOf(nil))
+	assert.Equal(t, StatusFail, StatusOf(errors.New("down")))
+	assert.Equal(t, StatusWarn, StatusOf(Warn(errors.New("slow"))))
+	assert.Nil(t, Warn(nil))
+}
+
+func TestWarnKeepsCause(t *testing.T) {
+	err := Warn(context.DeadlineExceeded)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, context.DeadlineExceeded.Error(), err.Error())
+}
+
+func TestStatusWorse(t *testing.T) {
+	assert.Equal(t, StatusWarn, StatusPass.Worse(StatusWarn))
+	assert.Equal(t, StatusFail, StatusWarn.Worse(StatusFail))
+	assert.Equal(t, StatusFail, StatusFail.Worse(StatusPass))
+	assert.Equal(t, StatusPass, StatusPass.Worse(StatusPass))
+}
+
+func TestSoft(t *testing.T) {
+	check := Soft(passOnlyCheck{pass: false})
+
+	assert.True(t, check.Pass())
+	assert.Equal(t, "pass only", check.Name())
+
+	err := AsContextCheck(check).Check(context.Background())
+	assert.Equal(t, StatusWarn, StatusOf(err))
+	assert.ErrorIs(t, err, ErrCheckFailed)
+
+	assert.NoError(t, AsContextCheck(Soft(passOnlyCheck{pass: true})).Check(context.Background()))
+}
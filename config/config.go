@@ -1,28 +1,244 @@
 package config
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/events"
+)
+
+// ErrorDetail controls how much of a failed check's error is put into the
+// response body. Error messages may contain hosts, user names or other
+// details that should not be exposed on a public endpoint.
+type ErrorDetail int
+
+const (
+	// ErrorDetailNone omits failure reasons.
+	ErrorDetailNone ErrorDetail = iota
+	// ErrorDetailSummary reports only the kind of failure: "timeout",
+	// "canceled" or "failed".
+	ErrorDetailSummary
+	// ErrorDetailFull reports the error message returned by the check.
+	ErrorDetailFull
+)
+
+// Message returns the part of err reported with d, or "" for a nil err.
+func (d ErrorDetail) Message(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	switch d {
+	case ErrorDetailFull:
+		return err.Error()
+	case ErrorDetailSummary:
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return "timeout"
+		case errors.Is(err, context.Canceled):
+			return "canceled"
+		default:
+			return "failed"
+		}
+	default:
+		return ""
+	}
+}
+
+// Format is the response body format of the health endpoints.
+type Format int
+
+const (
+	// FormatDefault responds with controllers.Report as JSON.
+	FormatDefault Format = iota
+	// FormatHealthJSON responds with application/health+json as described
+	// in draft-inadarei-api-health-check.
+	FormatHealthJSON
+)
+
+// ServiceInfo describes the service in application/health+json responses.
+type ServiceInfo struct {
+	Version     string
+	ReleaseID   string
+	ServiceID   string
+	Description string
+}
+
+// ProbeConfig is the route of one probe type. Zero status codes fall back
+// to the ones of Config; an empty Path disables the probe.
+type ProbeConfig struct {
+	Path           string
+	StatusOK       int
+	StatusNotOK    int
+	StatusDegraded int
+}
+
+// SchedulerConfig configures the background evaluation of checks used by
+// gin_healthcheck.NewScheduled.
+type SchedulerConfig struct {
+	// Interval between two runs of a check, unless the check sets its own
+	// with checks.WithInterval. Zero uses DefaultSchedulerInterval.
+	Interval time.Duration
+	// MaxAge after which a cached result counts as failed. Zero disables it.
+	MaxAge time.Duration
+}
+
 type Config struct {
 	HealthPath  string
 	Method      string
 	StatusOK    int
 	StatusNotOK int
+	// StatusDegraded is returned when no check failed but at least one
+	// reported a warning. StatusOK is used when it is zero.
+	StatusDegraded int
+	// Policy aggregates the statuses of all critical checks into the status
+	// of the response. Nil uses checks.PolicyAll.
+	Policy checks.Policy
+
+	// Timeout is the deadline of a whole health evaluation and CheckTimeout
+	// the deadline of every single check. Both apply on top of the request
+	// context; zero disables them.
+	Timeout      time.Duration
+	CheckTimeout time.Duration
+
+	// MaxConcurrency limits the number of checks running at the same time
+	// in one evaluation. Critical checks start before optional ones. Zero
+	// runs all checks at once.
+	MaxConcurrency int
+
+	// DeepAccess decides whether a request may evaluate deep checks, see
+	// checks.Deep. Nil allows every request.
+	DeepAccess func(r *http.Request) bool
 
+	// Coalesce lets concurrent requests of the health endpoint share one
+	// evaluation of the checks. MinInterval serves the result of the last
+	// evaluation to requests within MinInterval of its start; zero disables
+	// it. Both do not apply to filtered requests. Such evaluations are not
+	// canceled with the request that started them, so they are bounded by
+	// Timeout or, if it is zero, by DefaultCoalesceTimeout. Requests waiting
+	// for a shared evaluation give up when they are canceled.
+	Coalesce    bool
+	MinInterval time.Duration
+
+	// ErrorDetail is ErrorDetailSummary in DefaultConfig, so that error
+	// messages are only exposed when opted in with ErrorDetailFull.
+	ErrorDetail ErrorDetail
+
+	// Format is used unless the request asks for application/json or
+	// application/health+json in its Accept header.
+	Format  Format
+	Service ServiceInfo
+
+	// Liveness, Readiness and Startup are the routes mounted by
+	// gin_healthcheck.NewProbes.
+	Liveness  ProbeConfig
+	Readiness ProbeConfig
+	Startup   ProbeConfig
+
+	Scheduler SchedulerConfig
+
+	// Events receives an event for every status change of a check and of
+	// the aggregated status. Nil disables events.
+	Events *events.Bus
+
+	// Hooks observe every evaluation, check and response.
+	Hooks []Hook
+
+	// PanicHandler is called with the name of every check that panicked,
+	// e.g. to forward the panic to an error tracker. The panic is reported
+	// as a failure of the check either way.
+	PanicHandler func(check string, err *checks.PanicError)
+
+	// FailureNotification.Chan is sent to synchronously after a request
+	// failed Threshold times in a row, and with nil once it recovers.
+	// Concurrent requests send in the order their results were counted. If
+	// Window is set, it is sent to when the failures within the window
+	// reach its limits instead, and with nil once they drop below them. If
+	// Checks is set as well, the window is also applied to every critical
+	// check in unfiltered evaluations: a *CheckFailedError is sent when a
+	// check reaches its limits and a *CheckRecoveredError once it drops
+	// below them, both without blocking. Crossings of the window are
+	// published on Events as well. Clock returns the current time for time
+	// windows and defaults to time.Now.
+	// Of the probes mounted by gin_healthcheck.NewProbes, only the readiness
+	// probe sends notifications.
+	//
+	// Deprecated: Use Events, which does not block health requests and
+	// reports which check failed.
 	FailureNotification struct {
 		Threshold uint32
 		Chan      chan error
+		Window    *Window
+		Checks    bool
+		Clock     func() time.Time
 	}
 }
 
+// Window is a sliding window failure policy. It covers the evaluations of
+// the last Duration or, if Duration is zero, the last Size evaluations. Its
+// limits are reached when at least Failures evaluations within the window
+// failed and more than Ratio of them failed. Zero limits are ignored; with
+// both zero, a single failure reaches them.
+type Window struct {
+	Duration time.Duration
+	Size     int
+	Failures int
+	Ratio    float64
+}
+
+// DefaultSchedulerInterval is the interval of scheduled checks when neither
+// the check nor SchedulerConfig sets one.
+const DefaultSchedulerInterval = 10 * time.Second
+
+// DefaultCoalesceTimeout is the deadline of evaluations shared between
+// requests when Config.Timeout is zero.
+const DefaultCoalesceTimeout = 30 * time.Second
+
 func DefaultConfig() Config {
-	return Config{
-		HealthPath:  "/healthz",
-		Method:      "GET",
-		StatusOK:    200,
-		StatusNotOK: 503,
-		FailureNotification: struct {
-			Threshold uint32
-			Chan      chan error
-		}{
-			Threshold: 1,
+	c := Config{
+		HealthPath:     "/healthz",
+		Method:         "GET",
+		StatusOK:       200,
+		StatusNotOK:    503,
+		StatusDegraded: 200,
+		ErrorDetail:    ErrorDetailSummary,
+		Liveness:       ProbeConfig{Path: "/livez"},
+		Readiness:      ProbeConfig{Path: "/readyz"},
+		Startup:        ProbeConfig{Path: "/startupz"},
+		Scheduler: SchedulerConfig{
+			Interval: DefaultSchedulerInterval,
+			MaxAge:   30 * time.Second,
 		},
 	}
+	c.FailureNotification.Threshold = 1
+	return c
+}
+
+// Probe returns the config of the probe endpoint, with its status codes
+// merged into the top level ones.
+func (c Config) Probe(probe checks.Probe) Config {
+	var p ProbeConfig
+	switch probe {
+	case checks.Liveness:
+		p = c.Liveness
+	case checks.Readiness:
+		p = c.Readiness
+	case checks.Startup:
+		p = c.Startup
+	}
+
+	c.HealthPath = p.Path
+	if p.StatusOK != 0 {
+		c.StatusOK = p.StatusOK
+	}
+	if p.StatusNotOK != 0 {
+		c.StatusNotOK = p.StatusNotOK
+	}
+	if p.StatusDegraded != 0 {
+		c.StatusDegraded = p.StatusDegraded
+	}
+	return c
 }
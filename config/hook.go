@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+// Hook observes health evaluations, for example to export metrics or
+// traces. Its methods are called concurrently for different checks. Their
+// context holds the Scope of the evaluation or response, see ScopeOf.
+type Hook interface {
+	// StartEvaluation is called before the checks of one evaluation run.
+	// The returned context is passed to StartCheck and EndEvaluation.
+	StartEvaluation(ctx context.Context) context.Context
+	// EndEvaluation is called with the aggregated status of an evaluation.
+	EndEvaluation(ctx context.Context, status checks.Status)
+	// StartCheck is called before a check runs. The returned context is
+	// passed to the check and to EndCheck.
+	StartCheck(ctx context.Context, name string) context.Context
+	// EndCheck is called with the result of a check.
+	EndCheck(ctx context.Context, name string, status checks.Status, err error, duration time.Duration)
+	// Served is called for every response of a health endpoint.
+	Served(ctx context.Context, status checks.Status)
+}
+
+// Scope describes which checks an evaluation or a response covers.
+type Scope struct {
+	// Full is set when all but the deep checks of an endpoint were
+	// evaluated, and not only the checks selected by a filter or a single
+	// check. The background runs of a scheduler are full as well.
+	Full bool
+	// Probe is the probe of the endpoint, see gin_healthcheck.NewProbes.
+	Probe checks.Probe
+}
+
+type scopeKey struct{}
+
+// WithScope returns a copy of ctx holding scope.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeOf returns the scope in ctx, or the zero Scope.
+func ScopeOf(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/nethttp"
+)
+
+// CheckParam is the route parameter holding the ID or name of the check
+// evaluated by CheckController.
+const CheckParam = "check"
+
+// CheckController evaluates only the check whose ID (see checks.IDOf) or
+// name is in the CheckParam route parameter, e.g. /healthz/:check.
+func CheckController(checkList []checks.Check, config config.Config) gin.HandlerFunc {
+	return CheckControllerOf(checker.New(checkList, config))
+}
+
+// RegistryCheckController is CheckController for the checks in registry.
+func RegistryCheckController(registry *checks.Registry, config config.Config) gin.HandlerFunc {
+	return CheckControllerOf(checker.NewFromRegistry(registry, config))
+}
+
+// CheckControllerOf is CheckController for the checks of ch. Mounted next
+// to Controller(ch), both endpoints share the state of the checks.
+func CheckControllerOf(ch *checker.Checker) gin.HandlerFunc {
+	return wrap(nethttp.CheckHandler(ch))
+}
+
+// ScheduledCheckController serves the latest result of the check in the
+// CheckParam route parameter from s.
+func ScheduledCheckController(s *Scheduler) gin.HandlerFunc {
+	return wrap(nethttp.CheckHandler(s))
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+func TestCheckController(t *testing.T) {
+	os.Setenv("CHECK_CONTROLLER_VAR", "set")
+	defer os.Unsetenv("CHECK_CONTROLLER_VAR")
+
+	counting := &CountingCheck{}
+	router := gin.New()
+	router.GET("/healthcheck/:check", CheckController([]checks.Check{
+		checks.NewEnvCheck("CHECK_CONTROLLER_VAR"),
+		checks.Configure(FailingCheck{}, checks.WithID("failing")),
+		counting,
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name: `Environmental variable "CHECK_CONTROLLER_VAR"`,
+		Pass: true,
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck/environmental-variable-check-controller-var", "", 200, string(response))
+	assertRequest(t, router, "GET", "/healthcheck/"+url.PathEscape(`Environmental variable "CHECK_CONTROLLER_VAR"`), "", 200, string(response))
+
+	response, _ = json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck/failing", "", 503, string(response))
+
+	assert.Equal(t, int32(0), counting.runs.Load())
+
+	assertRequest(t, router, "GET", "/healthcheck/unknown", "", 404, `{"error":"unknown check \"unknown\""}`)
+}
+
+func TestRegistryCheckController(t *testing.T) {
+	registry := checks.NewRegistry()
+	router := gin.New()
+	router.GET("/healthcheck/:check", RegistryCheckController(registry, conf))
+
+	assertRequest(t, router, "GET", "/healthcheck/succeeding-check", "", 404, `{"error":"unknown check \"succeeding-check\""}`)
+
+	assert.NoError(t, registry.Register(SucceedingCheck{}))
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck/succeeding-check", "", 200, string(response))
+}
+
+func TestScheduledCheckController(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Scheduler.Interval = time.Hour
+
+	s := NewScheduler([]checks.Check{SucceedingCheck{}, FailingCheck{}}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		for _, status := range s.Report().Checks {
+			if status.Timestamp.IsZero() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+
+	router := gin.New()
+	router.GET("/healthcheck/:check", ScheduledCheckController(s))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck/failing-check", "", 503, string(response))
+	assertRequest(t, router, "GET", "/healthcheck/other", "", 404, `{"error":"unknown check \"other\""}`)
+}
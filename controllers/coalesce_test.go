@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// BlockingCheck counts its runs and blocks until release is closed.
+type BlockingCheck struct {
+	runs    atomic.Int32
+	release chan struct{}
+}
+
+func (c *BlockingCheck) Pass() bool {
+	return c.Check(context.Background()) == nil
+}
+
+func (c *BlockingCheck) Check(ctx context.Context) error {
+	c.runs.Add(1)
+	<-c.release
+	return nil
+}
+
+func (c *BlockingCheck) Name() string {
+	return "Blocking Check"
+}
+
+func TestCoalesce(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Coalesce = true
+
+	blocking := &BlockingCheck{release: make(chan struct{})}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{blocking}, conf))
+
+	const requests = 5
+	reports := make([]Report, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/healthcheck", nil)
+			router.ServeHTTP(res, req)
+			assert.Equal(t, 200, res.Code)
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &reports[i]))
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return blocking.runs.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(blocking.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), blocking.runs.Load())
+	shared := 0
+	for _, report := range reports {
+		assert.Equal(t, checks.StatusPass, report.Status)
+		assert.False(t, report.Cached)
+		if report.Shared {
+			shared++
+		}
+	}
+	assert.Equal(t, requests-1, shared)
+
+	serve(router, "/healthcheck")
+	assert.Equal(t, int32(2), blocking.runs.Load(), "finished evaluations are not shared")
+	assert.NotContains(t, res.Body.String(), `"shared"`)
+}
+
+func TestMinInterval(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.MinInterval = time.Hour
+
+	counting := &CountingCheck{}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{counting}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{Name: "Counting Check", Pass: true}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+
+	cached := expectedReport([]CheckStatus{{Name: "Counting Check", Pass: true}})
+	cached.Cached = true
+	response, _ = json.Marshal(cached)
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+	assert.Equal(t, int32(1), counting.runs.Load())
+
+	assertRequest(t, router, "GET", "/healthcheck?include=none", "", 400, `{"error":"unknown tag \"none\""}`)
+}
+
+func TestCoalesceWaitersGiveUp(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Coalesce = true
+
+	blocking := &BlockingCheck{release: make(chan struct{})}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{blocking}, conf))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/healthcheck", nil)
+		router.ServeHTTP(res, req)
+		assert.Equal(t, 200, res.Code)
+	}()
+	assert.Eventually(t, func() bool { return blocking.runs.Load() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/healthcheck", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, 503, res.Code)
+	assert.Contains(t, res.Body.String(), `"error":"canceled"`)
+
+	close(blocking.release)
+	<-done
+	assert.Equal(t, int32(1), blocking.runs.Load())
+}
+
+// DeadlineCheck records the deadline of the context it is run with.
+type DeadlineCheck struct {
+	deadline chan time.Time
+}
+
+func (c DeadlineCheck) Pass() bool {
+	return c.Check(context.Background()) == nil
+}
+
+func (c DeadlineCheck) Check(ctx context.Context) error {
+	deadline, _ := ctx.Deadline()
+	c.deadline <- deadline
+	return nil
+}
+
+func (c DeadlineCheck) Name() string {
+	return "Deadline Check"
+}
+
+func TestCoalesceDefaultTimeout(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Coalesce = true
+
+	check := DeadlineCheck{deadline: make(chan time.Time, 1)}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{check}, conf))
+
+	start := time.Now()
+	serve(router, "/healthcheck")
+	assert.WithinDuration(t, start.Add(config.DefaultCoalesceTimeout), <-check.deadline, time.Second)
+}
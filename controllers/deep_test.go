@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+func TestDeepChecks(t *testing.T) {
+	counting := &CountingCheck{}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		SucceedingCheck{},
+		checks.Configure(counting, checks.Deep()),
+	}, conf))
+
+	shallow := expectedReport([]CheckStatus{{Name: "Succeeding Check", Pass: true}})
+	shallow.SkippedDeep = []string{"Counting Check"}
+	response, _ := json.Marshal(shallow)
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+	assertRequest(t, router, "GET", "/healthcheck?deep=false", "", 200, string(response))
+	assert.Equal(t, int32(0), counting.runs.Load())
+
+	deep := expectedReport([]CheckStatus{
+		{Name: "Succeeding Check", Pass: true},
+		{Name: "Counting Check", Pass: true},
+	})
+	deep.Filter = &Filter{Deep: true}
+	response, _ = json.Marshal(deep)
+	assertRequest(t, router, "GET", "/healthcheck?deep=true", "", 200, string(response))
+	assert.Equal(t, int32(1), counting.runs.Load())
+}
+
+func TestDeepAccess(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.DeepAccess = func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer operator"
+	}
+
+	router := gin.New()
+	checkList := []checks.Check{checks.Configure(SucceedingCheck{}, checks.Deep(), checks.WithTags("db"))}
+	router.GET("/healthcheck", HealthcheckController(checkList, conf))
+	router.GET("/healthcheck/:check", CheckController(checkList, conf))
+
+	forbidden := `{"error":"deep checks are not allowed"}`
+	assertRequest(t, router, "GET", "/healthcheck?deep=true", "", 403, forbidden)
+	assertRequest(t, router, "GET", "/healthcheck/succeeding-check", "", 403, forbidden)
+
+	filtered := expectedReport([]CheckStatus{})
+	filtered.Filter = &Filter{Include: []string{"db"}}
+	filtered.SkippedDeep = []string{"Succeeding Check"}
+	response, _ := json.Marshal(filtered)
+	assertRequest(t, router, "GET", "/healthcheck?include=db", "", 200, string(response))
+
+	for _, path := range []string{"/healthcheck?deep=true", "/healthcheck/succeeding-check"} {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer operator")
+		router.ServeHTTP(res, req)
+		assert.Equal(t, 200, res.Code, path)
+	}
+}
+
+func TestScheduledDeepChecks(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = time.Hour
+
+	s := NewScheduler([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.Deep()),
+		SucceedingCheck{},
+	}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		report, err := s.RunCheck(context.Background(), "Failing Check")
+		return err == nil && !report.Checks[0].Timestamp.IsZero()
+	}, time.Second, time.Millisecond)
+
+	report := s.Report()
+	assert.Equal(t, checks.StatusPass, report.Status)
+	assert.Len(t, report.Checks, 1)
+	assert.Equal(t, []string{"Failing Check"}, report.SkippedDeep)
+
+	router := gin.New()
+	router.GET("/healthcheck", ScheduledController(s))
+	router.GET("/healthcheck/:check", ScheduledCheckController(s))
+
+	serve(router, "/healthcheck?deep=true")
+	assert.Equal(t, 503, res.Code)
+	serve(router, "/healthcheck/failing-check")
+	assert.Equal(t, 503, res.Code)
+	serve(router, "/healthcheck/succeeding-check")
+	assert.Equal(t, 200, res.Code)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// OrderedCheck fails if the check it depends on has not finished yet.
+type OrderedCheck struct {
+	name  string
+	delay time.Duration
+	after *OrderedCheck
+	done  atomic.Bool
+}
+
+func (c *OrderedCheck) Pass() bool {
+	return c.Check(context.Background()) == nil
+}
+
+func (c *OrderedCheck) Check(ctx context.Context) error {
+	time.Sleep(c.delay)
+	if c.after != nil && !c.after.done.Load() {
+		return errors.New("dependency has not finished")
+	}
+	c.done.Store(true)
+	return nil
+}
+
+func (c *OrderedCheck) Name() string {
+	return c.name
+}
+
+func TestDependencySkipsDependents(t *testing.T) {
+	counting := &CountingCheck{}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(counting, checks.DependsOn("gateway")),
+		checks.Configure(FailingCheck{}, checks.WithID("gateway")),
+		SucceedingCheck{},
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:    "Counting Check",
+		Pass:    false,
+		Error:   `skipped: dependency "Failing Check" failed`,
+		Skipped: true,
+	}, {
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}, {
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+	assert.Equal(t, int32(0), counting.runs.Load())
+}
+
+func TestDependencyOrder(t *testing.T) {
+	gateway := &OrderedCheck{name: "gateway", delay: 50 * time.Millisecond}
+	mongo := &OrderedCheck{name: "mongo", after: gateway}
+	redis := &OrderedCheck{name: "redis", after: gateway}
+	other := &OrderedCheck{name: "other", delay: 50 * time.Millisecond}
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(mongo, checks.DependsOn("gateway")),
+		checks.Configure(redis, checks.DependsOn("gateway")),
+		gateway,
+		other,
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{
+		{Name: "mongo", Pass: true},
+		{Name: "redis", Pass: true},
+		{Name: "gateway", Pass: true},
+		{Name: "other", Pass: true},
+	}))
+
+	start := time.Now()
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "independent checks run in parallel")
+}
+
+func TestSchedulerSkipsDependents(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = 10 * time.Millisecond
+
+	counting := &CountingCheck{}
+	s := NewScheduler([]checks.Check{
+		FailingCheck{},
+		checks.Configure(counting, checks.DependsOn("Failing Check")),
+	}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		return s.Report().Checks[1].Skipped
+	}, time.Second, time.Millisecond)
+	assert.LessOrEqual(t, counting.runs.Load(), int32(1), "runs at most once before the dependency ran")
+}
+
+func TestOptionalDependencyFailureDoesNotCascade(t *testing.T) {
+	counting := &CountingCheck{}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.WithID("analytics"), checks.Optional()),
+		checks.Configure(counting, checks.DependsOn("analytics")),
+	}, config.DefaultConfig()))
+
+	serve(router, "/healthcheck")
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, int32(1), counting.runs.Load())
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	assert.Equal(t, checks.StatusPass, report.Status)
+	assert.False(t, report.Checks[1].Skipped)
+}
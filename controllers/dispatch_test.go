@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// TrackingCheck records the order in which checks start and how many of
+// them run at the same time. With gates, it announces its start on starts
+// and runs until its gate is closed.
+type TrackingCheck struct {
+	name    string
+	tracker *tracker
+}
+
+type tracker struct {
+	lock    sync.Mutex
+	running int
+	max     int
+	started []string
+
+	starts chan string
+	gates  map[string]chan struct{}
+}
+
+func (c TrackingCheck) Pass() bool {
+	return c.Check(context.Background()) == nil
+}
+
+func (c TrackingCheck) Check(ctx context.Context) error {
+	c.tracker.lock.Lock()
+	c.tracker.running++
+	c.tracker.max = max(c.tracker.max, c.tracker.running)
+	c.tracker.started = append(c.tracker.started, c.name)
+	c.tracker.lock.Unlock()
+
+	if gate, ok := c.tracker.gates[c.name]; ok {
+		c.tracker.starts <- c.name
+		<-gate
+	}
+
+	c.tracker.lock.Lock()
+	c.tracker.running--
+	c.tracker.lock.Unlock()
+	return nil
+}
+
+func (c TrackingCheck) Name() string {
+	return c.name
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.MaxConcurrency = 2
+
+	tr := &tracker{starts: make(chan string), gates: make(map[string]chan struct{})}
+	for _, name := range []string{"optional", "first", "second", "third"} {
+		tr.gates[name] = make(chan struct{})
+	}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(TrackingCheck{"optional", tr}, checks.Optional()),
+		TrackingCheck{"first", tr},
+		TrackingCheck{"second", tr},
+		TrackingCheck{"third", tr},
+	}, conf))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve(router, "/healthcheck")
+	}()
+
+	// The critical checks take both slots, and the remaining ones start in
+	// the order of their priority as the slots are released.
+	assert.ElementsMatch(t, []string{"first", "second"}, []string{<-tr.starts, <-tr.starts})
+	close(tr.gates["first"])
+	assert.Equal(t, "third", <-tr.starts)
+	close(tr.gates["second"])
+	assert.Equal(t, "optional", <-tr.starts)
+	close(tr.gates["third"])
+	close(tr.gates["optional"])
+	<-done
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, 2, tr.max)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	waits := map[string]time.Duration{}
+	for _, status := range report.Checks {
+		waits[status.Name] = status.Wait
+	}
+	assert.Less(t, waits["first"], waits["third"])
+	assert.Less(t, waits["third"], waits["optional"])
+}
+
+func TestMaxConcurrencyWithDependencies(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.MaxConcurrency = 1
+
+	tr := &tracker{}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(TrackingCheck{"dependent", tr}, checks.DependsOn("gateway")),
+		checks.Configure(TrackingCheck{"gateway", tr}, checks.Optional()),
+		checks.Configure(FailingCheck{}, checks.DependsOn("gateway")),
+		checks.Configure(SucceedingCheck{}, checks.DependsOn("Failing Check")),
+	}, conf))
+
+	serve(router, "/healthcheck")
+	assert.Equal(t, 503, res.Code)
+	assert.Equal(t, []string{"gateway", "dependent"}, tr.started)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	assert.True(t, report.Checks[3].Skipped)
+	assert.Equal(t, `skipped: dependency "Failing Check" failed`, report.Checks[3].Error)
+}
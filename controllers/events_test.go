@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/events"
+)
+
+func TestEventsOnTransitions(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	subscription := conf.Events.Subscribe(10)
+
+	controlled := &ControlledCheck{willPass: true}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{controlled, SucceedingCheck{}}, conf))
+
+	serve(router, "/healthcheck")
+	received := drain(subscription)
+	assert.Len(t, received, 3)
+	assertEvent(t, received, "Controlled Check", "", checks.StatusPass)
+	assertEvent(t, received, "Succeeding Check", "", checks.StatusPass)
+	assertEvent(t, received, "", "", checks.StatusPass)
+
+	serve(router, "/healthcheck")
+	assert.Empty(t, drain(subscription))
+
+	controlled.willPass = false
+	serve(router, "/healthcheck")
+	received = drain(subscription)
+	assert.Len(t, received, 2)
+	event := assertEvent(t, received, "Controlled Check", checks.StatusPass, checks.StatusFail)
+	assert.ErrorIs(t, event.Err, checks.ErrCheckFailed)
+	event = assertEvent(t, received, "", checks.StatusPass, checks.StatusFail)
+	assert.ErrorIs(t, event.Err, ErrHealthcheckFailed)
+}
+
+func TestEventsDoNotBlockRequests(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	subscription := conf.Events.Subscribe(0)
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}}, conf))
+
+	serve(router, "/healthcheck")
+	serve(router, "/healthcheck")
+
+	assert.Equal(t, uint64(2), subscription.Dropped())
+}
+
+func TestProbeEvents(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	conf.FailureNotification.Chan = make(chan error, 10)
+	subscription := conf.Events.Subscribe(10)
+
+	checkList := []checks.Check{
+		checks.Configure(FailingCheck{}, checks.WithProbes(checks.Liveness, checks.Readiness)),
+	}
+	router := gin.New()
+	router.GET("/livez", ProbeController(checks.Liveness, checkList, conf))
+	router.GET("/readyz", ProbeController(checks.Readiness, checkList, conf))
+
+	serve(router, "/livez")
+	serve(router, "/readyz")
+
+	received := drain(subscription)
+	assert.Len(t, received, 4)
+	for idx, probe := range []checks.Probe{checks.Liveness, checks.Liveness, checks.Readiness, checks.Readiness} {
+		assert.Equal(t, probe, received[idx].Probe)
+	}
+	assert.Len(t, conf.FailureNotification.Chan, 1, "only the readiness probe notifies")
+}
+
+func drain(s *events.Subscription) []events.Event {
+	var received []events.Event
+	for {
+		select {
+		case event := <-s.C:
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+func assertEvent(t *testing.T, received []events.Event, check string, from, to checks.Status) events.Event {
+	t.Helper()
+	for _, event := range received {
+		if event.Check == check && event.Aggregate == (check == "") {
+			assert.Equal(t, from, event.Old)
+			assert.Equal(t, to, event.New)
+			assert.False(t, event.Time.IsZero())
+			return event
+		}
+	}
+	t.Errorf("no event for %q in %v", check, received)
+	return events.Event{}
+}
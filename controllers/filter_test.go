@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+func taggedChecks() []checks.Check {
+	return []checks.Check{
+		checks.Configure(SucceedingCheck{}, checks.WithTags("db")),
+		checks.Configure(FailingCheck{}, checks.WithTags("external")),
+		checks.Configure(&ControlledCheck{willPass: true}, checks.WithTags("db", "cache")),
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController(taggedChecks(), conf))
+
+	succeeding := CheckStatus{Name: "Succeeding Check", Pass: true}
+	controlled := CheckStatus{Name: "Controlled Check", Pass: true}
+	failing := CheckStatus{Name: "Failing Check", Pass: false, Error: "check failed"}
+
+	tests := []struct {
+		query    string
+		status   int
+		filter   *Filter
+		statuses []CheckStatus
+	}{
+		{
+			query:    "",
+			status:   503,
+			statuses: []CheckStatus{succeeding, failing, controlled},
+		},
+		{
+			query:    "?include=db",
+			status:   200,
+			filter:   &Filter{Include: []string{"db"}},
+			statuses: []CheckStatus{succeeding, controlled},
+		},
+		{
+			query:    "?exclude=external",
+			status:   200,
+			filter:   &Filter{Exclude: []string{"external"}},
+			statuses: []CheckStatus{succeeding, controlled},
+		},
+		{
+			query:    "?include=db&exclude=cache",
+			status:   200,
+			filter:   &Filter{Include: []string{"db"}, Exclude: []string{"cache"}},
+			statuses: []CheckStatus{succeeding},
+		},
+		{
+			query:    "?include=cache,external",
+			status:   503,
+			filter:   &Filter{Include: []string{"cache", "external"}},
+			statuses: []CheckStatus{failing, controlled},
+		},
+		{
+			query:    "?include=cache&include=external",
+			status:   503,
+			filter:   &Filter{Include: []string{"cache", "external"}},
+			statuses: []CheckStatus{failing, controlled},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			report := expectedReport(append([]CheckStatus{}, tt.statuses...))
+			report.Filter = tt.filter
+			response, _ := json.Marshal(report)
+			assertRequest(t, router, "GET", "/healthcheck"+tt.query, "", tt.status, string(response))
+		})
+	}
+}
+
+func TestFilterUnknownTag(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController(taggedChecks(), conf))
+
+	assertRequest(t, router, "GET", "/healthcheck?include=db&exclude=queue", "", 400, `{"error":"unknown tag \"queue\""}`)
+}
+
+func TestFilteredRequestsDoNotNotify(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.FailureNotification.Chan = make(chan error, 1)
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController(taggedChecks(), conf))
+
+	serve(router, "/healthcheck?include=external")
+	assert.Equal(t, 503, res.Code)
+	assert.Len(t, conf.FailureNotification.Chan, 0)
+
+	serve(router, "/healthcheck")
+	assert.Len(t, conf.FailureNotification.Chan, 1)
+}
+
+func TestScheduledControllerFilter(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = time.Hour
+
+	s := NewScheduler(taggedChecks(), conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		for _, status := range s.Report().Checks {
+			if status.Timestamp.IsZero() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+
+	router := gin.New()
+	router.GET("/healthcheck", ScheduledController(s))
+
+	report := expectedReport([]CheckStatus{{Name: "Succeeding Check", Pass: true}})
+	report.Filter = &Filter{Exclude: []string{"cache", "external"}}
+	response, _ := json.Marshal(report)
+	assertRequest(t, router, "GET", "/healthcheck?exclude=cache,external", "", 200, string(response))
+
+	assertRequest(t, router, "GET", "/healthcheck?include=queue", "", 400, `{"error":"unknown tag \"queue\""}`)
+}
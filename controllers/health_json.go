@@ -0,0 +1,14 @@
+package controllers
+
+import (
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+)
+
+const HealthJSONContentType = checker.HealthJSONContentType
+
+// HealthResponse is the application/health+json representation of a
+// Report, as described in draft-inadarei-api-health-check.
+type HealthResponse = checker.HealthResponse
+
+// HealthCheckItem is one entry of the checks object of a HealthResponse.
+type HealthCheckItem = checker.HealthCheckItem
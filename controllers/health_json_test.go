@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+func TestHealthJSONFormat(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Format = config.FormatHealthJSON
+	conf.Service = config.ServiceInfo{
+		Version:     "1",
+		ReleaseID:   "1.2.3",
+		ServiceID:   "orders",
+		Description: "order service",
+	}
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.WithComponentType("datastore")),
+		checks.Soft(SucceedingCheck{}),
+	}, conf))
+
+	serve(router, "/healthcheck")
+
+	assert.Equal(t, 503, res.Code)
+	assert.Equal(t, HealthJSONContentType, res.Header().Get("Content-Type"))
+
+	var response HealthResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &response))
+	assert.Equal(t, checks.StatusFail, response.Status)
+	assert.Equal(t, "1", response.Version)
+	assert.Equal(t, "1.2.3", response.ReleaseID)
+	assert.Equal(t, "orders", response.ServiceID)
+	assert.Equal(t, "order service", response.Description)
+	assert.Len(t, response.Checks, 2)
+
+	failing := response.Checks["Failing Check:responseTime"]
+	if assert.Len(t, failing, 1) {
+		assert.Equal(t, "datastore", failing[0].ComponentType)
+		assert.Equal(t, checks.StatusFail, failing[0].Status)
+		assert.Equal(t, "ms", failing[0].ObservedUnit)
+		assert.Equal(t, "check failed", failing[0].Output)
+		assert.False(t, failing[0].Time.IsZero())
+	}
+
+	succeeding := response.Checks["Succeeding Check:responseTime"]
+	if assert.Len(t, succeeding, 1) {
+		assert.Equal(t, checks.StatusPass, succeeding[0].Status)
+		assert.Empty(t, succeeding[0].Output)
+	}
+}
+
+func TestHealthJSONAcceptHeader(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{SucceedingCheck{}}, conf))
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{accept: "", contentType: "application/json; charset=utf-8"},
+		{accept: "*/*", contentType: "application/json; charset=utf-8"},
+		{accept: "application/health+json", contentType: HealthJSONContentType},
+		{accept: "text/html, application/health+json;q=0.9", contentType: HealthJSONContentType},
+		{accept: "application/json", contentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/healthcheck", nil)
+			req.Header.Set("Accept", tt.accept)
+			router.ServeHTTP(res, req)
+
+			assert.Equal(t, 200, res.Code)
+			assert.Equal(t, tt.contentType, res.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAcceptJSONOverridesHealthJSONFormat(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Format = config.FormatHealthJSON
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{SucceedingCheck{}}, conf))
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthcheck", nil)
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(res, req)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	assert.Equal(t, checks.StatusPass, report.Status)
+	assert.Len(t, report.Checks, 1)
+}
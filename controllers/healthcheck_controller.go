@@ -1,67 +1,78 @@
 package controllers
 
 import (
-	"errors"
-	"sync"
+	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
-	"github.com/tavsec/gin-healthcheck/checks"
-	"github.com/tavsec/gin-healthcheck/config"
-	"golang.org/x/sync/errgroup"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/nethttp"
 )
 
-type CheckStatus struct {
-	Name string `json:"name"`
-	Pass bool   `json:"pass"`
-}
+// CheckStatus is the result of a single check, see checker.CheckStatus.
+type CheckStatus = checker.CheckStatus
 
-var ErrHealthcheckFailed = errors.New("healthcheck failed")
+// Report is the response body of the health endpoint, see checker.Report.
+type Report = checker.Report
 
-func HealthcheckController(checks []checks.Check, config config.Config) gin.HandlerFunc {
-	var lock sync.Mutex
-	var failureInARow uint32
+// Filter selects checks by their tags, see checker.Filter.
+type Filter = checker.Filter
 
-	fn := func(c *gin.Context) {
-		var eg errgroup.Group
+// CheckFailedError is sent on config.Config.FailureNotification.Chan when
+// the failures of a check reach the limits of the notification window.
+type CheckFailedError = checker.CheckFailedError
 
-		statuses := make([]CheckStatus, len(checks))
-		httpStatus := config.StatusOK
-		for idx, check := range checks {
-			captureCheck := check
-			captureIdx := idx
-			eg.Go(func() error {
-				pass := captureCheck.Pass()
-				statuses[captureIdx] = CheckStatus{
-					Name: captureCheck.Name(),
-					Pass: pass,
-				}
+// CheckRecoveredError is sent on config.Config.FailureNotification.Chan when
+// the failures of a check drop below the limits of the notification window.
+type CheckRecoveredError = checker.CheckRecoveredError
 
-				if !pass {
-					return ErrHealthcheckFailed
-				}
-				return nil
-			})
-		}
+var ErrHealthcheckFailed = checker.ErrHealthcheckFailed
 
-		lock.Lock()
-		if err := eg.Wait(); err != nil {
-			httpStatus = config.StatusNotOK
-			failureInARow += 1
+func HealthcheckController(checkList []checks.Check, config config.Config) gin.HandlerFunc {
+	return Controller(checker.New(checkList, config))
+}
 
-			if failureInARow >= config.FailureNotification.Threshold &&
-				config.FailureNotification.Chan != nil {
-				config.FailureNotification.Chan <- err
-			}
-		} else {
-			if failureInARow != 0 && config.FailureNotification.Chan != nil {
-				failureInARow = 0
-				config.FailureNotification.Chan <- nil
-			}
-		}
-		lock.Unlock()
+// RegistryController evaluates the checks registered in registry at the
+// time of each request.
+func RegistryController(registry *checks.Registry, config config.Config) gin.HandlerFunc {
+	return Controller(checker.NewFromRegistry(registry, config))
+}
 
-		c.JSON(httpStatus, statuses)
+// Controller serves the reports of ch. The include, exclude and deep query
+// parameters are passed to ch as a checker.Filter.
+func Controller(ch *checker.Checker) gin.HandlerFunc {
+	return wrap(nethttp.Handler(ch))
+}
+
+// ProbeController returns the handler of one probe type, see
+// checker.NewProbe. The startup probe keeps passing once all of its checks
+// have passed.
+func ProbeController(probe checks.Probe, checkList []checks.Check, config config.Config) gin.HandlerFunc {
+	return wrap(nethttp.ProbeHandler(probe, checkList, config))
+}
+
+// wrap adapts a handler of the nethttp package, which responds with the
+// same bodies and status codes, to gin.
+func wrap(h http.Handler) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, request(c))
 	}
 
 	return gin.HandlerFunc(fn)
 }
+
+// request returns the request of c, with the CheckParam route parameter as
+// path value.
+func request(c *gin.Context) *http.Request {
+	req := c.Request
+	if req == nil {
+		req = &http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: make(http.Header)}
+	}
+	if ref := c.Param(CheckParam); ref != "" {
+		req = req.WithContext(req.Context())
+		req.SetPathValue(nethttp.CheckParam, ref)
+	}
+	return req
+}
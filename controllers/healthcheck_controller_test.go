@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"net/http"
@@ -12,8 +13,8 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
-	"github.com/tavsec/gin-healthcheck/checks"
-	"github.com/tavsec/gin-healthcheck/config"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,6 +27,7 @@ var (
 func init() {
 	gin.SetMode(gin.TestMode)
 	conf = config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
 }
 
 type FailingCheck struct{}
@@ -39,6 +41,17 @@ func (c FailingCheck) Name() string {
 	return "Failing Check"
 }
 
+type SucceedingCheck struct{}
+
+var _ checks.Check = SucceedingCheck{}
+
+func (c SucceedingCheck) Pass() bool {
+	return true
+}
+func (c SucceedingCheck) Name() string {
+	return "Succeeding Check"
+}
+
 type SlowCheck struct{}
 
 var _ checks.Check = SlowCheck{}
@@ -67,7 +80,7 @@ func (c *ControlledCheck) Name() string {
 func TestHealthcheckController(t *testing.T) {
 	router := gin.New()
 	router.GET("/healthcheck", HealthcheckController([]checks.Check{}, conf))
-	assertRequest(t, router, "GET", "/healthcheck", "", 200, "[]")
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, `{"status":"pass","checks":[]}`)
 }
 
 func TestHealthcheckControllerWithSqlCheck(t *testing.T) {
@@ -80,10 +93,10 @@ func TestHealthcheckControllerWithSqlCheck(t *testing.T) {
 
 	router.GET("/healthcheck", HealthcheckController([]checks.Check{checks.SqlCheck{Sql: db}}, conf))
 
-	response, err := json.Marshal([]CheckStatus{{
+	response, err := json.Marshal(expectedReport([]CheckStatus{{
 		Name: "*sqlmock.mockDriver",
 		Pass: true,
-	}})
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
 }
@@ -92,10 +105,11 @@ func TestNoPass(t *testing.T) {
 	router := gin.New()
 	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}}, conf))
 
-	response, _ := json.Marshal([]CheckStatus{{
-		Name: "Failing Check",
-		Pass: false,
-	}})
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
 	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
 }
 
@@ -111,19 +125,20 @@ func TestSwitchResultBetweenCall(t *testing.T) {
 
 	mock.ExpectPing().WillReturnError(nil)
 
-	response, err := json.Marshal([]CheckStatus{{
+	response, err := json.Marshal(expectedReport([]CheckStatus{{
 		Name: "*sqlmock.mockDriver",
 		Pass: true,
-	}})
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
 
 	mock.ExpectPing().WillReturnError(driver.ErrBadConn)
 
-	response, err = json.Marshal([]CheckStatus{{
-		Name: "*sqlmock.mockDriver",
-		Pass: false,
-	}})
+	response, err = json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "*sqlmock.mockDriver",
+		Pass:  false,
+		Error: "driver: bad connection",
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
 }
@@ -138,13 +153,14 @@ func TestParallelCheck(t *testing.T) {
 	router := gin.New()
 	router.GET("/healthcheck", HealthcheckController([]checks.Check{checks.SqlCheck{Sql: db}, FailingCheck{}}, conf))
 
-	response, _ := json.Marshal([]CheckStatus{{
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
 		Name: "*sqlmock.mockDriver",
 		Pass: true,
 	}, {
-		Name: "Failing Check",
-		Pass: false,
-	}})
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
 	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
 }
 
@@ -157,26 +173,378 @@ func TestParallelCheckSpeed(t *testing.T) {
 	assert.Less(t, time.Since(start), 3*time.Second)
 }
 
+func TestCheckTimeout(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.CheckTimeout = 50 * time.Millisecond
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{SlowCheck{}, FailingCheck{}}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Slow Check",
+		Pass:  false,
+		Error: "context deadline exceeded",
+	}, {
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+
+	start := time.Now()
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestOverallTimeout(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Timeout = 50 * time.Millisecond
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{SlowCheck{}}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Slow Check",
+		Pass:  false,
+		Error: "context deadline exceeded",
+	}}))
+
+	start := time.Now()
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRequestContextCancelsChecks(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{SlowCheck{}}, conf))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res = httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/healthcheck", nil)
+
+	start := time.Now()
+	router.ServeHTTP(res, req)
+
+	assert.Equal(t, 503, res.Code)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestErrorDetail(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail config.ErrorDetail
+		check  checks.Check
+		want   string
+	}{
+		{name: "full", detail: config.ErrorDetailFull, check: FailingCheck{}, want: "check failed"},
+		{name: "summary failure", detail: config.ErrorDetailSummary, check: FailingCheck{}, want: "failed"},
+		{name: "summary timeout", detail: config.ErrorDetailSummary, check: SlowCheck{}, want: "timeout"},
+		{name: "none", detail: config.ErrorDetailNone, check: FailingCheck{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.DefaultConfig()
+			conf.ErrorDetail = tt.detail
+			conf.CheckTimeout = 10 * time.Millisecond
+
+			router := gin.New()
+			router.GET("/healthcheck", HealthcheckController([]checks.Check{tt.check}, conf))
+
+			response, _ := json.Marshal(expectedReport([]CheckStatus{{
+				Name:  tt.check.Name(),
+				Pass:  false,
+				Error: tt.want,
+			}}))
+			assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+		})
+	}
+}
+
+func TestDurationAndTimestamp(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}}, conf))
+
+	before := time.Now()
+	res = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthcheck", nil)
+	router.ServeHTTP(res, req)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	if assert.Len(t, report.Checks, 1) {
+		assert.Greater(t, report.Checks[0].Duration, time.Duration(0))
+		assert.False(t, report.Checks[0].Timestamp.Before(before.Truncate(time.Second)))
+		assert.False(t, report.Checks[0].Timestamp.After(time.Now()))
+	}
+}
+
+func TestDegraded(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{checks.Soft(FailingCheck{}), SucceedingCheck{}}, conf))
+
+	response, _ := json.Marshal(Report{
+		Status: checks.StatusWarn,
+		Checks: []CheckStatus{{
+			Name:   "Failing Check",
+			Status: checks.StatusWarn,
+			Pass:   true,
+			Error:  "check failed",
+		}, {
+			Name:   "Succeeding Check",
+			Status: checks.StatusPass,
+			Pass:   true,
+		}},
+	})
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+}
+
+func TestDegradedStatusCode(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.StatusDegraded = 207
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{checks.Soft(FailingCheck{}), FailingCheck{}}, conf))
+	router.GET("/degraded", HealthcheckController([]checks.Check{checks.Soft(FailingCheck{})}, conf))
+
+	res = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthcheck", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, 503, res.Code)
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/degraded", nil)
+	router.ServeHTTP(res, req)
+	assert.Equal(t, 207, res.Code)
+}
+
+func TestOptionalCheck(t *testing.T) {
+	router := gin.New()
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.FailureNotification.Chan = make(chan error, 1)
+	conf.FailureNotification.Threshold = 1
+
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.Optional()),
+		SucceedingCheck{},
+	}, conf))
+
+	response, _ := json.Marshal(Report{
+		Status: checks.StatusPass,
+		Checks: []CheckStatus{{
+			Name:     "Failing Check",
+			Status:   checks.StatusFail,
+			Pass:     false,
+			Error:    "check failed",
+			Optional: true,
+		}, {
+			Name:   "Succeeding Check",
+			Status: checks.StatusPass,
+			Pass:   true,
+		}},
+	})
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+	assert.Empty(t, conf.FailureNotification.Chan)
+}
+
+func TestAggregateCheck(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.AtLeast("replicas", 1, FailingCheck{}, SucceedingCheck{}),
+	}, conf))
+
+	response, _ := json.Marshal(Report{
+		Status: checks.StatusWarn,
+		Checks: []CheckStatus{{
+			Name:   "replicas",
+			Status: checks.StatusWarn,
+			Pass:   true,
+			Error:  "1 of 2 checks passed",
+			Checks: []CheckStatus{{
+				Name:   "Failing Check",
+				Status: checks.StatusFail,
+				Pass:   false,
+				Error:  "check failed",
+			}, {
+				Name:   "Succeeding Check",
+				Status: checks.StatusPass,
+				Pass:   true,
+			}},
+		}},
+	})
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+}
+
+func TestRetryAttempts(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Retry(FailingCheck{}, 3, time.Millisecond),
+		checks.Retry(SucceedingCheck{}, 3, time.Millisecond),
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:     "Failing Check",
+		Pass:     false,
+		Error:    "check failed",
+		Attempts: 3,
+	}, {
+		Name:     "Succeeding Check",
+		Pass:     true,
+		Attempts: 1,
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+}
+
+func TestBreakerState(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Breaker(FailingCheck{}, 2, time.Hour),
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:    "Failing Check",
+		Pass:    false,
+		Error:   "check failed",
+		Breaker: &checks.BreakerState{State: checks.CircuitClosed, Failures: 1},
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+	assert.Contains(t, res.Body.String(), `"breaker":{"state":"closed","failures":1}`)
+
+	serve(router, "/healthcheck")
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	assert.Equal(t, checks.CircuitOpen, report.Checks[0].Breaker.State)
+	assert.False(t, report.Checks[0].Breaker.OpenedAt.IsZero())
+}
+
+func TestPolicy(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Policy = checks.PolicyAny()
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}, SucceedingCheck{}}, conf))
+	router.GET("/failing", HealthcheckController([]checks.Check{FailingCheck{}}, conf))
+
+	response, _ := json.Marshal(Report{
+		Status: checks.StatusWarn,
+		Checks: expectedReport([]CheckStatus{{
+			Name:  "Failing Check",
+			Pass:  false,
+			Error: "check failed",
+		}, {
+			Name: "Succeeding Check",
+			Pass: true,
+		}}).Checks,
+	})
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+
+	response, _ = json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	assertRequest(t, router, "GET", "/failing", "", 503, string(response))
+}
+
+func TestProbeController(t *testing.T) {
+	router := gin.New()
+	checkList := []checks.Check{
+		checks.Configure(FailingCheck{}, checks.WithProbes(checks.Readiness)),
+		checks.Configure(SucceedingCheck{}, checks.WithProbes(checks.Liveness, checks.Readiness)),
+	}
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Readiness.StatusNotOK = 500
+
+	router.GET("/livez", ProbeController(checks.Liveness, checkList, conf))
+	router.GET("/readyz", ProbeController(checks.Readiness, checkList, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assertRequest(t, router, "GET", "/livez", "", 200, string(response))
+
+	response, _ = json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}, {
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assertRequest(t, router, "GET", "/readyz", "", 500, string(response))
+}
+
+func TestStartupProbeLatches(t *testing.T) {
+	router := gin.New()
+	controlled := &ControlledCheck{willPass: false}
+	checkList := []checks.Check{checks.Configure(controlled, checks.WithProbes(checks.Startup))}
+
+	router.GET("/startupz", ProbeController(checks.Startup, checkList, conf))
+
+	failureResponse, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Controlled Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	successResponse, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name: "Controlled Check",
+		Pass: true,
+	}}))
+
+	assertRequest(t, router, "GET", "/startupz", "", 503, string(failureResponse))
+
+	controlled.willPass = true
+	assertRequest(t, router, "GET", "/startupz", "", 200, string(successResponse))
+
+	controlled.willPass = false
+	assertRequest(t, router, "GET", "/startupz", "", 200, string(successResponse))
+}
+
+func TestStartupProbeIgnoresFilteredRequests(t *testing.T) {
+	router := gin.New()
+	checkList := []checks.Check{
+		checks.Configure(SucceedingCheck{}, checks.WithProbes(checks.Startup), checks.WithTags("x")),
+		checks.Configure(FailingCheck{}, checks.WithProbes(checks.Startup)),
+	}
+
+	router.GET("/startupz", ProbeController(checks.Startup, checkList, conf))
+
+	serve(router, "/startupz?include=x")
+	assert.Equal(t, 200, res.Code)
+	serve(router, "/startupz")
+	assert.Equal(t, 503, res.Code, "a filtered request must not latch the probe")
+}
+
 func TestNotification(t *testing.T) {
 	router := gin.New()
 
 	controlled := &ControlledCheck{willPass: true}
 	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
 	conf.FailureNotification.Chan = make(chan error, 1)
 	defer close(conf.FailureNotification.Chan)
 	conf.FailureNotification.Threshold = 3
 
 	router.GET("/healthcheck", HealthcheckController([]checks.Check{controlled}, conf))
 
-	successResponse, err := json.Marshal([]CheckStatus{{
+	successResponse, err := json.Marshal(expectedReport([]CheckStatus{{
 		Name: "Controlled Check",
 		Pass: true,
-	}})
+	}}))
 	assert.NoError(t, err)
-	failureResponse, err := json.Marshal([]CheckStatus{{
-		Name: "Controlled Check",
-		Pass: false,
-	}})
+	failureResponse, err := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Controlled Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
 	assert.NoError(t, err)
 
 	var wg sync.WaitGroup
@@ -224,6 +592,12 @@ func TestNotification(t *testing.T) {
 	assert.Error(t, ErrHealthcheckFailed, errNotification)
 }
 
+func serve(router *gin.Engine, path string) {
+	res = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(res, req)
+}
+
 func assertRequest(t *testing.T, router *gin.Engine, method string, path string, body string, assertStatus int, assertBody string) {
 	res = httptest.NewRecorder()
 	req, _ := http.NewRequest(method, path, strings.NewReader(body))
@@ -233,11 +607,43 @@ func assertRequest(t *testing.T, router *gin.Engine, method string, path string,
 	if res.Code != assertStatus {
 		t.Errorf("expected %d, got %d", assertStatus, res.Code)
 	}
-	if b := res.Body.String(); b != assertBody {
+	if b := normalizeBody(res.Body.String()); b != assertBody {
 		t.Errorf("expected %q, got %q", assertBody, b)
 	}
 }
 
+// expectedReport builds the expected report for statuses, deriving each check's
+// status from Pass unless it is set explicitly.
+func expectedReport(statuses []CheckStatus) Report {
+	report := Report{Status: checks.StatusPass, Checks: statuses}
+	for i := range statuses {
+		if statuses[i].Status == "" {
+			statuses[i].Status = checks.StatusFail
+			if statuses[i].Pass {
+				statuses[i].Status = checks.StatusPass
+			}
+		}
+		if !statuses[i].Optional {
+			report.Status = report.Status.Worse(statuses[i].Status)
+		}
+	}
+	return report
+}
+
+// normalizeBody zeroes the fields of a response that change on every request.
+func normalizeBody(body string) string {
+	var report Report
+	if err := json.Unmarshal([]byte(body), &report); err != nil || report.Status == "" {
+		return body
+	}
+	normalizeStatuses(report.Checks)
+	normalized, err := json.Marshal(report)
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
+
 func BenchmarkCheckLatency(b *testing.B) {
 	f := HealthcheckController([]checks.Check{SlowCheck{}, SlowCheck{}}, conf)
 
@@ -249,3 +655,12 @@ func BenchmarkCheckLatency(b *testing.B) {
 		f(c)
 	}
 }
+
+func normalizeStatuses(statuses []CheckStatus) {
+	for i := range statuses {
+		statuses[i].Duration = 0
+		statuses[i].Timestamp = time.Time{}
+		statuses[i].Age = 0
+		normalizeStatuses(statuses[i].Checks)
+	}
+}
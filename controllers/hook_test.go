@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+type hookKey struct{}
+
+type RecordingHook struct {
+	lock  sync.Mutex
+	calls []string
+}
+
+func (h *RecordingHook) record(call string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.calls = append(h.calls, call)
+}
+
+func (h *RecordingHook) StartEvaluation(ctx context.Context) context.Context {
+	h.record("start evaluation")
+	return context.WithValue(ctx, hookKey{}, "evaluation")
+}
+
+func (h *RecordingHook) EndEvaluation(ctx context.Context, status checks.Status) {
+	h.record("end evaluation " + ctx.Value(hookKey{}).(string) + " " + string(status))
+}
+
+func (h *RecordingHook) StartCheck(ctx context.Context, name string) context.Context {
+	h.record("start " + name + " in " + ctx.Value(hookKey{}).(string))
+	return context.WithValue(ctx, hookKey{}, name)
+}
+
+func (h *RecordingHook) EndCheck(ctx context.Context, name string, status checks.Status, err error, duration time.Duration) {
+	h.record("end " + ctx.Value(hookKey{}).(string) + " " + string(status))
+}
+
+func (h *RecordingHook) Served(ctx context.Context, status checks.Status) {
+	h.record("served " + string(status))
+}
+
+func TestHooks(t *testing.T) {
+	hook := &RecordingHook{}
+	conf := config.DefaultConfig()
+	conf.Hooks = []config.Hook{hook}
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}, SucceedingCheck{}}, conf))
+	serve(router, "/healthcheck")
+
+	assert.Equal(t, "start evaluation", hook.calls[0])
+	assert.Equal(t, []string{
+		"end Failing Check fail",
+		"end Succeeding Check pass",
+		"start Failing Check in evaluation",
+		"start Succeeding Check in evaluation",
+	}, sorted(hook.calls[1:5]))
+	assert.Equal(t, []string{"end evaluation evaluation fail", "served fail"}, hook.calls[5:])
+}
+
+func sorted(calls []string) []string {
+	calls = append([]string{}, calls...)
+	sort.Strings(calls)
+	return calls
+}
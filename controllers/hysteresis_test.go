@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+func TestThresholds(t *testing.T) {
+	controlled := &ControlledCheck{willPass: true}
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(controlled, checks.WithThresholds(2, 3)),
+	}, conf))
+
+	expect := func(code int, status, raw checks.Status) {
+		t.Helper()
+		result := CheckStatus{
+			Name:      "Controlled Check",
+			Status:    status,
+			Pass:      status != checks.StatusFail,
+			RawStatus: raw,
+		}
+		if raw == checks.StatusFail {
+			result.Error = "check failed"
+		}
+		response, _ := json.Marshal(expectedReport([]CheckStatus{result}))
+		assertRequest(t, router, "GET", "/healthcheck", "", code, string(response))
+	}
+
+	expect(200, checks.StatusPass, checks.StatusPass)
+
+	controlled.willPass = false
+	expect(200, checks.StatusPass, checks.StatusFail)
+	expect(503, checks.StatusFail, checks.StatusFail)
+
+	controlled.willPass = true
+	expect(503, checks.StatusFail, checks.StatusPass)
+	controlled.willPass = false
+	expect(503, checks.StatusFail, checks.StatusFail)
+
+	controlled.willPass = true
+	expect(503, checks.StatusFail, checks.StatusPass)
+	expect(503, checks.StatusFail, checks.StatusPass)
+	expect(200, checks.StatusPass, checks.StatusPass)
+
+	controlled.willPass = false
+	expect(200, checks.StatusPass, checks.StatusFail)
+}
+
+func TestNoThresholds(t *testing.T) {
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.WithThresholds(1, 1)),
+	}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+}
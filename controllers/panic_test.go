@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+type PanickingCheck struct {
+	client *struct{ addr string }
+}
+
+func (c PanickingCheck) Pass() bool {
+	return c.client.addr != ""
+}
+
+func (c PanickingCheck) Name() string {
+	return "Panicking Check"
+}
+
+func TestPanickingCheck(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	var lock sync.Mutex
+	var panics []string
+	conf.PanicHandler = func(check string, err *checks.PanicError) {
+		lock.Lock()
+		defer lock.Unlock()
+		panics = append(panics, check+": "+err.Error())
+	}
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		PanickingCheck{},
+		checks.AnyOf("any", SucceedingCheck{}, PanickingCheck{}),
+	}, conf))
+
+	serve(router, "/healthcheck")
+	assert.Equal(t, 503, res.Code)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+	if assert.Len(t, report.Checks, 2) {
+		status := report.Checks[0]
+		assert.Equal(t, checks.StatusFail, status.Status)
+		assert.Equal(t, "panic: runtime error: invalid memory address or nil pointer dereference", status.Error)
+		assert.True(t, strings.HasPrefix(status.Stack, "github.com/tavsec/gin-healthcheck/v2/controllers.PanickingCheck.Pass("), status.Stack)
+
+		assert.Equal(t, checks.StatusWarn, report.Checks[1].Status)
+		assert.NotEmpty(t, report.Checks[1].Checks[1].Stack)
+	}
+
+	msg := "Panicking Check: panic: runtime error: invalid memory address or nil pointer dereference"
+	assert.ElementsMatch(t, []string{msg, msg}, panics)
+}
+
+func TestPanickingCheckStackDetail(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailSummary
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{PanickingCheck{}}, conf))
+
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Panicking Check",
+		Pass:  false,
+		Error: "failed",
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+func TestRegistryController(t *testing.T) {
+	registry := checks.NewRegistry()
+	router := gin.New()
+	router.GET("/healthcheck", RegistryController(registry, conf))
+
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, `{"status":"pass","checks":[]}`)
+
+	assert.NoError(t, registry.Register(FailingCheck{}))
+	response, _ := json.Marshal(expectedReport([]CheckStatus{{
+		Name:  "Failing Check",
+		Pass:  false,
+		Error: "check failed",
+	}}))
+	assertRequest(t, router, "GET", "/healthcheck", "", 503, string(response))
+
+	assert.NoError(t, registry.Replace("Failing Check", checks.Soft(FailingCheck{})))
+	response, _ = json.Marshal(Report{
+		Status: checks.StatusWarn,
+		Checks: []CheckStatus{{
+			Name:   "Failing Check",
+			Status: checks.StatusWarn,
+			Pass:   true,
+			Error:  "check failed",
+		}},
+	})
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, string(response))
+
+	assert.NoError(t, registry.Unregister("Failing Check"))
+	assertRequest(t, router, "GET", "/healthcheck", "", 200, `{"status":"pass","checks":[]}`)
+}
+
+func TestRegistryControllerConcurrentChanges(t *testing.T) {
+	registry := checks.NewRegistry()
+	handler := RegistryController(registry, conf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			check := checks.NewEnvCheck(fmt.Sprint("TENANT_", i))
+			assert.NoError(t, registry.Register(check))
+			assert.NoError(t, registry.Unregister(check.Name()))
+		}(i)
+		go func() {
+			defer wg.Done()
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			handler(c)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/nethttp"
+)
+
+// Scheduler runs checks in the background, see checker.Scheduler.
+type Scheduler = checker.Scheduler
+
+func NewScheduler(checkList []checks.Check, config config.Config) *Scheduler {
+	return checker.NewScheduler(checkList, config)
+}
+
+// ScheduledController serves the latest results of s instead of running the
+// checks on every request.
+func ScheduledController(s *Scheduler) gin.HandlerFunc {
+	return wrap(nethttp.Handler(s))
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+type CountingCheck struct {
+	runs atomic.Int32
+}
+
+func (c *CountingCheck) Pass() bool {
+	return c.Check(context.Background()) == nil
+}
+
+func (c *CountingCheck) Check(ctx context.Context) error {
+	c.runs.Add(1)
+	return nil
+}
+
+func (c *CountingCheck) Name() string {
+	return "Counting Check"
+}
+
+func TestSchedulerBeforeStart(t *testing.T) {
+	s := NewScheduler([]checks.Check{SucceedingCheck{}}, conf)
+
+	report := s.Report()
+	assert.Equal(t, checks.StatusFail, report.Status)
+	assert.Equal(t, "check has not run yet", report.Checks[0].Error)
+}
+
+func TestSchedulerRunsChecksInBackground(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = 10 * time.Millisecond
+
+	counting := &CountingCheck{}
+	hourly := &CountingCheck{}
+	s := NewScheduler([]checks.Check{counting, checks.Configure(hourly, checks.WithInterval(time.Hour))}, conf)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		return counting.runs.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	report := s.Report()
+	assert.Equal(t, checks.StatusPass, report.Status)
+	assert.Equal(t, int32(1), hourly.runs.Load())
+	assert.False(t, report.Checks[0].Timestamp.IsZero())
+}
+
+func TestSchedulerDefaultInterval(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler = config.SchedulerConfig{MaxAge: time.Minute}
+
+	counting := &CountingCheck{}
+	s := NewScheduler([]checks.Check{counting}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		return s.Report().Status == checks.StatusPass
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), counting.runs.Load())
+}
+
+func TestSchedulerStop(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = time.Millisecond
+
+	counting := &CountingCheck{}
+	s := NewScheduler([]checks.Check{counting}, conf)
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool {
+		return counting.runs.Load() >= 1
+	}, time.Second, time.Millisecond)
+	s.Stop()
+
+	runs := counting.runs.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, runs, counting.runs.Load())
+}
+
+func TestSchedulerStaleResult(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailFull
+	conf.Scheduler.Interval = time.Hour
+	conf.Scheduler.MaxAge = 20 * time.Millisecond
+
+	s := NewScheduler([]checks.Check{SucceedingCheck{}}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		return s.Report().Status == checks.StatusPass
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+
+	report := s.Report()
+	assert.Equal(t, checks.StatusFail, report.Status)
+	assert.Equal(t, "result is older than 20ms", report.Checks[0].Error)
+	assert.Greater(t, report.Checks[0].Age, 20*time.Millisecond)
+}
+
+func TestScheduledController(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = time.Hour
+
+	counting := &CountingCheck{}
+	s := NewScheduler([]checks.Check{counting}, conf)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool {
+		return s.Report().Status == checks.StatusPass
+	}, time.Second, time.Millisecond)
+
+	router := gin.New()
+	router.GET("/healthcheck", ScheduledController(s))
+
+	for i := 0; i < 3; i++ {
+		res = httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/healthcheck", nil)
+		router.ServeHTTP(res, req)
+
+		var report Report
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+		assert.Equal(t, 200, res.Code)
+		assert.Equal(t, checks.StatusPass, report.Status)
+		assert.Greater(t, report.Checks[0].Age, time.Duration(0))
+	}
+
+	assert.Equal(t, int32(1), counting.runs.Load())
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/events"
+)
+
+func TestNotificationWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	controlled := &ControlledCheck{}
+
+	conf := config.DefaultConfig()
+	conf.FailureNotification.Chan = make(chan error, 10)
+	conf.FailureNotification.Window = &config.Window{Duration: 5 * time.Minute, Failures: 3, Ratio: 0.5}
+	conf.FailureNotification.Checks = true
+	conf.FailureNotification.Clock = func() time.Time { return now }
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{controlled, SucceedingCheck{}}, conf))
+
+	request := func(pass bool) {
+		controlled.willPass = pass
+		serve(router, "/healthcheck")
+		now = now.Add(time.Minute)
+	}
+
+	request(false)
+	request(true)
+	request(false)
+	assert.Empty(t, conf.FailureNotification.Chan)
+	request(false)
+
+	failed := &CheckFailedError{}
+	if assert.Len(t, conf.FailureNotification.Chan, 2) {
+		assert.ErrorAs(t, <-conf.FailureNotification.Chan, &failed)
+		assert.Equal(t, "Controlled Check", failed.Check)
+		assert.ErrorIs(t, failed, ErrHealthcheckFailed)
+		assert.Equal(t, ErrHealthcheckFailed, <-conf.FailureNotification.Chan)
+	}
+
+	now = now.Add(time.Hour)
+	request(true)
+	recovered := &CheckRecoveredError{}
+	if assert.Len(t, conf.FailureNotification.Chan, 2) {
+		assert.ErrorAs(t, <-conf.FailureNotification.Chan, &recovered)
+		assert.Equal(t, "Controlled Check", recovered.Check)
+		assert.NotErrorIs(t, recovered, ErrHealthcheckFailed)
+		assert.NoError(t, <-conf.FailureNotification.Chan)
+	}
+}
+
+func TestNotificationWindowEvents(t *testing.T) {
+	controlled := &ControlledCheck{}
+
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	conf.FailureNotification.Window = &config.Window{Size: 2, Failures: 2}
+	conf.FailureNotification.Checks = true
+	subscription := conf.Events.Subscribe(10)
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{controlled}, conf))
+
+	serve(router, "/healthcheck")
+	assert.Empty(t, windowEvents(drain(subscription)))
+	serve(router, "/healthcheck")
+
+	received := windowEvents(drain(subscription))
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "Controlled Check", received[0].Check)
+		assert.Equal(t, checks.StatusFail, received[0].New)
+		assert.ErrorIs(t, received[0].Err, ErrHealthcheckFailed)
+		assert.True(t, received[1].Aggregate)
+		assert.Equal(t, checks.StatusFail, received[1].New)
+	}
+
+	controlled.willPass = true
+	serve(router, "/healthcheck")
+	received = windowEvents(drain(subscription))
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, checks.StatusPass, received[0].New)
+		assert.NoError(t, received[0].Err)
+		assert.Equal(t, checks.StatusPass, received[1].New)
+	}
+}
+
+func TestCheckNotificationsDoNotBlock(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	conf.FailureNotification.Chan = make(chan error, 1)
+	conf.FailureNotification.Window = &config.Window{Size: 1}
+	conf.FailureNotification.Checks = true
+	subscription := conf.Events.Subscribe(10)
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{FailingCheck{}}, conf))
+
+	full := errors.New("full")
+	conf.FailureNotification.Chan <- full
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve(router, "/healthcheck")
+	}()
+
+	// The aggregated status is published before it is sent, so by then the
+	// notification of the check has been dropped.
+	for event := range subscription.C {
+		if event.Aggregate && !event.Window {
+			break
+		}
+	}
+	assert.Equal(t, full, <-conf.FailureNotification.Chan)
+	assert.Equal(t, ErrHealthcheckFailed, <-conf.FailureNotification.Chan)
+	<-done
+	assert.Empty(t, conf.FailureNotification.Chan)
+}
+
+func windowEvents(received []events.Event) []events.Event {
+	var window []events.Event
+	for _, event := range received {
+		if event.Window {
+			window = append(window, event)
+		}
+	}
+	return window
+}
+
+func TestNotificationWindowIgnoresOptionalChecks(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Events = events.NewBus()
+	conf.FailureNotification.Chan = make(chan error, 10)
+	conf.FailureNotification.Window = &config.Window{Size: 2}
+	conf.FailureNotification.Checks = true
+	subscription := conf.Events.Subscribe(10)
+
+	router := gin.New()
+	router.GET("/healthcheck", HealthcheckController([]checks.Check{
+		checks.Configure(FailingCheck{}, checks.Optional()),
+		SucceedingCheck{},
+	}, conf))
+
+	serve(router, "/healthcheck")
+	serve(router, "/healthcheck")
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, conf.FailureNotification.Chan)
+	assert.Empty(t, windowEvents(drain(subscription)))
+}
+
+func TestNotificationWindowCountsFullEvaluationsOnly(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.FailureNotification.Chan = make(chan error, 10)
+	conf.FailureNotification.Window = &config.Window{Size: 2, Failures: 2}
+	conf.FailureNotification.Checks = true
+
+	checkList := []checks.Check{checks.Configure(FailingCheck{}, checks.WithTags("db"))}
+	router := gin.New()
+	ch := checker.New(checkList, conf)
+	router.GET("/healthcheck", Controller(ch))
+	router.GET("/healthcheck/:check", CheckControllerOf(ch))
+
+	serve(router, "/healthcheck/failing-check")
+	serve(router, "/healthcheck?include=db")
+	serve(router, "/healthcheck")
+	// The filtered requests are no samples of the windows, so neither the
+	// check nor the aggregated status reached two failures yet.
+	assert.Empty(t, conf.FailureNotification.Chan)
+
+	serve(router, "/healthcheck")
+	failed := &CheckFailedError{}
+	if assert.Len(t, conf.FailureNotification.Chan, 2) {
+		assert.ErrorAs(t, <-conf.FailureNotification.Chan, &failed)
+		assert.Equal(t, ErrHealthcheckFailed, <-conf.FailureNotification.Chan)
+	}
+}
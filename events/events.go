@@ -0,0 +1,104 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+// Event is published when the status of a check, or the aggregated status
+// of all checks, changes.
+type Event struct {
+	// Check is the name of the check. It is empty for aggregate events.
+	Check     string
+	Aggregate bool
+	// Probe is the probe whose endpoint evaluated the check, see
+	// gin_healthcheck.NewProbes. It is empty for other endpoints.
+	Probe checks.Probe
+	// Window is set for the events of a config.Window: New is StatusFail
+	// when the failures within the window reach its limits and StatusPass
+	// when they drop below them again.
+	Window bool
+	// Old is empty for the first result of a check.
+	Old  checks.Status
+	New  checks.Status
+	Err  error
+	Time time.Time
+}
+
+// Bus delivers events to any number of subscribers. Publishing never
+// blocks: events for subscribers whose buffer is full are dropped and
+// counted.
+type Bus struct {
+	lock        sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	dropped     atomic.Uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe returns a subscription buffering up to buffer events.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	ch := make(chan Event, buffer)
+	s := &Subscription{
+		C:   ch,
+		ch:  ch,
+		bus: b,
+	}
+
+	b.lock.Lock()
+	b.subscribers[s] = struct{}{}
+	b.lock.Unlock()
+
+	return s
+}
+
+func (b *Bus) Publish(event Event) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	for s := range b.subscribers {
+		select {
+		case s.ch <- event:
+		default:
+			s.dropped.Add(1)
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped over all subscribers.
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+type Subscription struct {
+	C <-chan Event
+
+	ch      chan Event
+	bus     *Bus
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// Dropped returns the number of events dropped because C was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops the delivery of events and closes C.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.bus.lock.Lock()
+		delete(s.bus.subscribers, s)
+		s.bus.lock.Unlock()
+
+		close(s.ch)
+	})
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+)
+
+func TestPublishToAllSubscribers(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe(1)
+	second := bus.Subscribe(1)
+
+	event := Event{Check: "redis", Old: checks.StatusPass, New: checks.StatusFail}
+	bus.Publish(event)
+
+	assert.Equal(t, event, <-first.C)
+	assert.Equal(t, event, <-second.C)
+}
+
+func TestPublishDropsWhenFull(t *testing.T) {
+	bus := NewBus()
+	full := bus.Subscribe(1)
+	unbuffered := bus.Subscribe(0)
+
+	bus.Publish(Event{Check: "first"})
+	bus.Publish(Event{Check: "second"})
+
+	assert.Equal(t, "first", (<-full.C).Check)
+	assert.Equal(t, uint64(1), full.Dropped())
+	assert.Equal(t, uint64(2), unbuffered.Dropped())
+	assert.Equal(t, uint64(3), bus.Dropped())
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := NewBus()
+	s := bus.Subscribe(1)
+
+	s.Unsubscribe()
+	s.Unsubscribe()
+	bus.Publish(Event{Check: "redis"})
+
+	_, ok := <-s.C
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), bus.Dropped())
+}
@@ -1,13 +1,150 @@
 package gin_healthcheck
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/gin-gonic/gin"
-	"github.com/tavsec/gin-healthcheck/checks"
-	"github.com/tavsec/gin-healthcheck/config"
-	"github.com/tavsec/gin-healthcheck/controllers"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/controllers"
+)
+
+var (
+	// ErrRouteConflict is returned when a health route is already
+	// registered or conflicts with a registered route.
+	ErrRouteConflict = errors.New("route conflict")
+	// ErrInvalidRoute is returned for an invalid method, a health path that
+	// does not start with a slash or another path gin does not accept.
+	ErrInvalidRoute = errors.New("invalid route")
 )
 
-func New(engine *gin.Engine, config config.Config, checks []checks.Check) error {
-	engine.Handle(config.Method, config.HealthPath, controllers.HealthcheckController(checks, config))
+// New mounts the health endpoint at config.HealthPath, and an endpoint
+// evaluating a single check by its ID or name below it. routes is usually a
+// *gin.Engine or a *gin.RouterGroup, whose middleware also applies to the
+// endpoints; middleware is added to the endpoints only. Both endpoints share
+// the state of the checks, such as thresholds and notifications. It returns
+// an error if two checks have the same ID, if checks depend on unknown checks
+// or on each other in a cycle, or if the routes conflict with registered
+// ones. See handle for the routes left registered on conflicts.
+func New(routes gin.IRoutes, config config.Config, checkList []checks.Check, middleware ...gin.HandlerFunc) error {
+	if err := checks.Validate(checkList); err != nil {
+		return err
+	}
+
+	return mount(routes, config, checker.New(checkList, config), middleware)
+}
+
+// NewWithRegistry mounts the health endpoint for the checks in registry.
+// Checks can be registered and unregistered while the endpoint is serving.
+func NewWithRegistry(routes gin.IRoutes, config config.Config, registry *checks.Registry, middleware ...gin.HandlerFunc) error {
+	return mount(routes, config, checker.NewFromRegistry(registry, config), middleware)
+}
+
+// mount registers the health endpoint and the endpoint of a single check,
+// both served by ch.
+func mount(routes gin.IRoutes, config config.Config, ch *checker.Checker, middleware []gin.HandlerFunc) error {
+	return handle(routes, config.Method, []endpoint{
+		{config.HealthPath, controllers.Controller(ch)},
+		{checkPath(config), controllers.CheckControllerOf(ch)},
+	}, middleware)
+}
+
+// NewProbes mounts the liveness, readiness and startup endpoints configured
+// in config. Every endpoint evaluates only the checks assigned to its probe
+// with checks.WithProbes.
+func NewProbes(routes gin.IRoutes, config config.Config, checkList []checks.Check, middleware ...gin.HandlerFunc) error {
+	if err := checks.Validate(checkList); err != nil {
+		return err
+	}
+
+	var endpoints []endpoint
+	for _, probe := range []checks.Probe{checks.Liveness, checks.Readiness, checks.Startup} {
+		probeConfig := config.Probe(probe)
+		if probeConfig.HealthPath == "" {
+			continue
+		}
+		endpoints = append(endpoints, endpoint{probeConfig.HealthPath, controllers.ProbeController(probe, checkList, config)})
+	}
+	return handle(routes, config.Method, endpoints, middleware)
+}
+
+// NewScheduled mounts the health endpoint in background mode: checks run on
+// their own intervals and requests are served from the latest results. The
+// returned scheduler has to be started by the caller.
+func NewScheduled(routes gin.IRoutes, config config.Config, checkList []checks.Check, middleware ...gin.HandlerFunc) (*controllers.Scheduler, error) {
+	if err := checks.Validate(checkList); err != nil {
+		return nil, err
+	}
+
+	scheduler := controllers.NewScheduler(checkList, config)
+	err := handle(routes, config.Method, []endpoint{
+		{config.HealthPath, controllers.ScheduledController(scheduler)},
+		{checkPath(config), controllers.ScheduledCheckController(scheduler)},
+	}, middleware)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler, nil
+}
+
+// checkPath is the route of the endpoint evaluating a single check.
+func checkPath(config config.Config) string {
+	return strings.TrimSuffix(config.HealthPath, "/") + "/:" + controllers.CheckParam
+}
+
+// endpoint is a route registered by handle.
+type endpoint struct {
+	path    string
+	handler gin.HandlerFunc
+}
+
+// handle registers the handlers of endpoints behind middleware. It checks
+// the method and paths before registering any of them, and returns
+// ErrRouteConflict if two endpoints have the same path. Conflicts with
+// routes registered before can only be detected by gin, which panics; handle
+// recovers and returns them as ErrRouteConflict too, but the endpoints
+// registered up to the conflicting one stay registered.
+func handle(routes gin.IRoutes, method string, endpoints []endpoint, middleware []gin.HandlerFunc) error {
+	if method == "" || strings.Trim(method, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("%w: method %q", ErrInvalidRoute, method)
+	}
+	seen := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		// An empty path is the path of the router group itself.
+		if e.path != "" && !strings.HasPrefix(e.path, "/") {
+			return fmt.Errorf("%w: %s %s: path must begin with '/'", ErrInvalidRoute, method, e.path)
+		}
+		if seen[e.path] {
+			return fmt.Errorf("%w: %s %s is mounted twice", ErrRouteConflict, method, e.path)
+		}
+		seen[e.path] = true
+	}
+
+	for _, e := range endpoints {
+		if err := register(routes, method, e, middleware); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register registers the handler of e. gin panics for duplicate and
+// conflicting routes, which register returns as ErrRouteConflict, and for
+// other paths it does not accept, which it returns as ErrInvalidRoute.
+func register(routes gin.IRoutes, method string, e endpoint, middleware []gin.HandlerFunc) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			kind := ErrInvalidRoute
+			if msg := fmt.Sprint(v); strings.Contains(msg, "already registered") || strings.Contains(msg, "conflicts with") {
+				kind = ErrRouteConflict
+			}
+			err = fmt.Errorf("%w: %s %s: %v", kind, method, e.path, v)
+		}
+	}()
+
+	routes.Handle(method, e.path, append(append([]gin.HandlerFunc{}, middleware...), e.handler)...)
 	return nil
 }
@@ -14,9 +14,10 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
-	"github.com/tavsec/gin-healthcheck/checks"
-	config2 "github.com/tavsec/gin-healthcheck/config"
-	"github.com/tavsec/gin-healthcheck/controllers"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	config2 "github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/controllers"
+	"github.com/tavsec/gin-healthcheck/v2/events"
 )
 
 var (
@@ -58,7 +59,7 @@ func TestHealthcheckResponseNoChecks(t *testing.T) {
 	config := config2.DefaultConfig()
 	New(router, config, []checks.Check{})
 
-	assertRequest(t, router, "GET", config.HealthPath, "", 200, "[]")
+	assertRequest(t, router, "GET", config.HealthPath, "", 200, `{"status":"pass","checks":[]}`)
 }
 
 func TestHealthcheckResponseMySqlCheck(t *testing.T) {
@@ -73,10 +74,10 @@ func TestHealthcheckResponseMySqlCheck(t *testing.T) {
 	c := []checks.Check{checks.SqlCheck{Sql: db}}
 	New(router, config, c)
 
-	response, err := json.Marshal([]controllers.CheckStatus{{
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
 		Name: "*sqlmock.mockDriver",
 		Pass: true,
-	}})
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", config.HealthPath, "", 200, string(response))
 }
@@ -84,6 +85,7 @@ func TestHealthcheckResponseMySqlCheck(t *testing.T) {
 func TestHealthcheckSwitchStateBetweenCall(t *testing.T) {
 	router := gin.Default()
 	config := config2.DefaultConfig()
+	config.ErrorDetail = config2.ErrorDetailFull
 	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -95,19 +97,20 @@ func TestHealthcheckSwitchStateBetweenCall(t *testing.T) {
 	c := []checks.Check{checks.SqlCheck{Sql: db}}
 	New(router, config, c)
 
-	response, err := json.Marshal([]controllers.CheckStatus{{
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
 		Name: "*sqlmock.mockDriver",
 		Pass: true,
-	}})
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", config.HealthPath, "", 200, string(response))
 
 	mock.ExpectPing().WillReturnError(driver.ErrBadConn)
 
-	response, err = json.Marshal([]controllers.CheckStatus{{
-		Name: "*sqlmock.mockDriver",
-		Pass: false,
-	}})
+	response, err = json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name:  "*sqlmock.mockDriver",
+		Pass:  false,
+		Error: "driver: bad connection",
+	}}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", config.HealthPath, "", 503, string(response))
 }
@@ -115,15 +118,16 @@ func TestHealthcheckSwitchStateBetweenCall(t *testing.T) {
 func TestHealthcheckContext(t *testing.T) {
 	router := gin.Default()
 	config := config2.DefaultConfig()
+	config.ErrorDetail = config2.ErrorDetailFull
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	c := []checks.Check{checks.NewContextCheck(ctx), checks.NewContextCheck(context.Background(), "test"), SucceedingCheck{}}
 	New(router, config, c)
 
-	response, err := json.Marshal([]controllers.CheckStatus{
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{
 		{
-			Name: "github.com/tavsec/gin-healthcheck.TestHealthcheckContext",
+			Name: "github.com/tavsec/gin-healthcheck/v2.TestHealthcheckContext",
 			Pass: true,
 		},
 		{
@@ -134,7 +138,7 @@ func TestHealthcheckContext(t *testing.T) {
 			Name: "Succeeding Check",
 			Pass: true,
 		},
-	})
+	}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", config.HealthPath, "", 200, string(response))
 
@@ -144,10 +148,11 @@ func TestHealthcheckContext(t *testing.T) {
 	// We need to give time to the goroutine to get scheduled before checking the status
 	time.Sleep(1 * time.Millisecond)
 
-	response, err = json.Marshal([]controllers.CheckStatus{
+	response, err = json.Marshal(expectedReport([]controllers.CheckStatus{
 		{
-			Name: "github.com/tavsec/gin-healthcheck.TestHealthcheckContext",
-			Pass: false,
+			Name:  "github.com/tavsec/gin-healthcheck/v2.TestHealthcheckContext",
+			Pass:  false,
+			Error: "context canceled",
 		},
 		{
 			Name: "test",
@@ -157,7 +162,7 @@ func TestHealthcheckContext(t *testing.T) {
 			Name: "Succeeding Check",
 			Pass: true,
 		},
-	})
+	}))
 	assert.NoError(t, err)
 	assertRequest(t, router, "GET", config.HealthPath, "", 503, string(response))
 }
@@ -166,6 +171,7 @@ func TestNotification(t *testing.T) {
 	router := gin.Default()
 
 	config := config2.DefaultConfig()
+	config.ErrorDetail = config2.ErrorDetailFull
 	config.FailureNotification.Chan = make(chan error, 1)
 	defer close(config.FailureNotification.Chan)
 	config.FailureNotification.Threshold = 3
@@ -184,12 +190,13 @@ func TestNotification(t *testing.T) {
 	c := []checks.Check{checks.NewContextCheck(ctx)}
 	New(router, config, c)
 
-	response, err := json.Marshal([]controllers.CheckStatus{
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{
 		{
-			Name: "github.com/tavsec/gin-healthcheck.TestNotification",
-			Pass: false,
+			Name:  "github.com/tavsec/gin-healthcheck/v2.TestNotification",
+			Pass:  false,
+			Error: "context canceled",
 		},
-	})
+	}))
 	assert.NoError(t, err)
 
 	cancel()
@@ -210,6 +217,56 @@ func TestNotification(t *testing.T) {
 	assert.Equal(t, controllers.ErrHealthcheckFailed, errNotification)
 }
 
+func TestNewWithRegistry(t *testing.T) {
+	router := gin.Default()
+	config := config2.DefaultConfig()
+	registry := checks.NewRegistry()
+
+	assert.NoError(t, NewWithRegistry(router, config, registry))
+	assertRequest(t, router, "GET", config.HealthPath, "", 200, `{"status":"pass","checks":[]}`)
+
+	assert.NoError(t, registry.Register(SucceedingCheck{}))
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assert.NoError(t, err)
+	assertRequest(t, router, "GET", config.HealthPath, "", 200, string(response))
+}
+
+func TestNewProbes(t *testing.T) {
+	router := gin.Default()
+	config := config2.DefaultConfig()
+	config.Startup.Path = ""
+
+	c := []checks.Check{
+		checks.Configure(SucceedingCheck{}, checks.WithProbes(checks.Liveness)),
+		checks.NewContextCheck(context.Background(), "ready"),
+	}
+	err := NewProbes(router, config, c)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Path)
+	}
+	assert.ElementsMatch(t, []string{"/livez", "/readyz"}, paths)
+
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assert.NoError(t, err)
+	assertRequest(t, router, "GET", "/livez", "", 200, string(response))
+
+	response, err = json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name: "ready",
+		Pass: true,
+	}}))
+	assert.NoError(t, err)
+	assertRequest(t, router, "GET", "/readyz", "", 200, string(response))
+}
+
 func assertRequest(t *testing.T, router *gin.Engine, method string, path string, body string, assertStatus int, assertBody string) {
 	res = httptest.NewRecorder()
 	req, _ := http.NewRequest(method, path, strings.NewReader(body))
@@ -219,7 +276,236 @@ func assertRequest(t *testing.T, router *gin.Engine, method string, path string,
 	if res.Code != assertStatus {
 		t.Errorf("expected %d, got %d", assertStatus, res.Code)
 	}
-	if b := res.Body.String(); b != assertBody {
+	if b := normalizeBody(res.Body.String()); b != assertBody {
 		t.Errorf("expected %q, got %q", assertBody, b)
 	}
 }
+
+// expectedReport builds the expected report for statuses, deriving each check's
+// status from Pass unless it is set explicitly.
+func expectedReport(statuses []controllers.CheckStatus) controllers.Report {
+	report := controllers.Report{Status: checks.StatusPass, Checks: statuses}
+	for i := range statuses {
+		if statuses[i].Status == "" {
+			statuses[i].Status = checks.StatusFail
+			if statuses[i].Pass {
+				statuses[i].Status = checks.StatusPass
+			}
+		}
+		report.Status = report.Status.Worse(statuses[i].Status)
+	}
+	return report
+}
+
+// normalizeBody zeroes the fields of a response that change on every request.
+func normalizeBody(body string) string {
+	var report controllers.Report
+	if err := json.Unmarshal([]byte(body), &report); err != nil || report.Status == "" {
+		return body
+	}
+	normalizeStatuses(report.Checks)
+	normalized, err := json.Marshal(report)
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
+
+func TestNewScheduled(t *testing.T) {
+	router := gin.Default()
+	config := config2.DefaultConfig()
+	config.ErrorDetail = config2.ErrorDetailFull
+	config.Scheduler.Interval = time.Hour
+
+	scheduler, err := NewScheduled(router, config, []checks.Check{SucceedingCheck{}})
+	assert.NoError(t, err)
+	assert.Equal(t, config.HealthPath, router.Routes()[0].Path)
+
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name:  "Succeeding Check",
+		Pass:  false,
+		Error: "check has not run yet",
+	}}))
+	assert.NoError(t, err)
+	assertRequest(t, router, "GET", config.HealthPath, "", 503, string(response))
+
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+
+	response, err = json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name: "Succeeding Check",
+		Pass: true,
+	}}))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return scheduler.Report().Status == checks.StatusPass
+	}, time.Second, time.Millisecond)
+	assertRequest(t, router, "GET", config.HealthPath, "", 200, string(response))
+}
+
+func TestSingleCheckRoute(t *testing.T) {
+	router := gin.Default()
+	config := config2.DefaultConfig()
+
+	err := New(router, config, []checks.Check{SucceedingCheck{}, checks.NewContextCheck(context.Background(), "signals")})
+	assert.NoError(t, err)
+
+	response, err := json.Marshal(expectedReport([]controllers.CheckStatus{{
+		Name: "signals",
+		Pass: true,
+	}}))
+	assert.NoError(t, err)
+	assertRequest(t, router, "GET", config.HealthPath+"/signals", "", 200, string(response))
+	assertRequest(t, router, "GET", config.HealthPath+"/redis", "", 404, `{"error":"unknown check \"redis\""}`)
+}
+
+func TestSingleCheckRouteSharesState(t *testing.T) {
+	router := gin.New()
+	config := config2.DefaultConfig()
+	config.Events = events.NewBus()
+	subscription := config.Events.Subscribe(10)
+
+	assert.NoError(t, New(router, config, []checks.Check{SucceedingCheck{}}))
+	for _, path := range []string{config.HealthPath, config.HealthPath + "/succeeding-check"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+
+	// The check passed on both endpoints, so only its first result and the
+	// aggregated status are published.
+	assert.Len(t, subscription.C, 2)
+}
+
+func TestNewRejectsDuplicateIDs(t *testing.T) {
+	router := gin.New()
+	checkList := []checks.Check{
+		checks.Configure(checks.NewContextCheck(context.Background(), "a"), checks.WithID("db")),
+		checks.Configure(checks.NewContextCheck(context.Background(), "b"), checks.WithID("db")),
+	}
+
+	assert.ErrorIs(t, New(router, config2.DefaultConfig(), checkList), checks.ErrDuplicateID)
+	assert.ErrorIs(t, NewProbes(router, config2.DefaultConfig(), checkList), checks.ErrDuplicateID)
+	assert.Empty(t, router.Routes())
+}
+
+func TestNewWithChecksOfTheSameName(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	replica, _, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	router := gin.New()
+	config := config2.DefaultConfig()
+	assert.NoError(t, New(router, config, []checks.Check{checks.SqlCheck{Sql: primary}, checks.SqlCheck{Sql: replica}}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", config.HealthPath+"/sqlmock-mockdriver", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `{"error":"ambiguous check \"sqlmock-mockdriver\": it refers to 2 checks"}`, w.Body.String())
+}
+
+func normalizeStatuses(statuses []controllers.CheckStatus) {
+	for i := range statuses {
+		statuses[i].Duration = 0
+		statuses[i].Timestamp = time.Time{}
+		statuses[i].Age = 0
+		normalizeStatuses(statuses[i].Checks)
+	}
+}
+
+func TestNewRejectsDependencyCycles(t *testing.T) {
+	router := gin.Default()
+	checkList := []checks.Check{
+		checks.Configure(checks.NewContextCheck(context.Background(), "a"), checks.DependsOn("b")),
+		checks.Configure(checks.NewContextCheck(context.Background(), "b"), checks.DependsOn("a")),
+	}
+
+	assert.ErrorIs(t, New(router, config2.DefaultConfig(), checkList), checks.ErrDependencyCycle)
+	assert.ErrorIs(t, NewProbes(router, config2.DefaultConfig(), checkList), checks.ErrDependencyCycle)
+	_, err := NewScheduled(router, config2.DefaultConfig(), checkList[:1])
+	assert.ErrorIs(t, err, checks.ErrUnknownDependency)
+	assert.Empty(t, router.Routes())
+}
+
+func TestNewOnRouterGroup(t *testing.T) {
+	router := gin.New()
+	internal := router.Group("/internal", func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	})
+	config := config2.DefaultConfig()
+
+	err := New(internal, config, []checks.Check{SucceedingCheck{}}, func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+	})
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/internal/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("Authorization", "Bearer token")
+	for _, path := range []string{"/internal/healthz", "/internal/healthz/succeeding-check"} {
+		req.URL.Path = path
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"), path)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/healthz", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNewRouteConflicts(t *testing.T) {
+	router := gin.New()
+	config := config2.DefaultConfig()
+	assert.NoError(t, New(router, config, []checks.Check{}))
+
+	assert.ErrorIs(t, New(router, config, []checks.Check{}), ErrRouteConflict)
+	assert.ErrorIs(t, NewWithRegistry(router, config, checks.NewRegistry()), ErrRouteConflict)
+	_, err := NewScheduled(router, config, []checks.Check{})
+	assert.ErrorIs(t, err, ErrRouteConflict)
+
+	router = gin.New()
+	router.GET("/healthz/*path", func(c *gin.Context) {})
+	err = New(router, config, []checks.Check{})
+	assert.ErrorIs(t, err, ErrRouteConflict)
+	assert.ErrorContains(t, err, "GET /healthz")
+
+	// Routes registered before the conflicting one are left in place.
+	router = gin.New()
+	router.GET("/healthz/:id", func(c *gin.Context) {})
+	assert.ErrorIs(t, New(router, config, []checks.Check{}), ErrRouteConflict)
+	assert.Len(t, router.Routes(), 2)
+
+	config.Readiness.Path = config.Liveness.Path
+	router = gin.New()
+	assert.ErrorIs(t, NewProbes(router, config, []checks.Check{}), ErrRouteConflict)
+	assert.Empty(t, router.Routes())
+}
+
+func TestNewInvalidRoutes(t *testing.T) {
+	for name, update := range map[string]func(c *config2.Config){
+		"empty method":     func(c *config2.Config) { c.Method = "" },
+		"lower case":       func(c *config2.Config) { c.Method = "get" },
+		"relative path":    func(c *config2.Config) { c.HealthPath = "healthz" },
+		"unnamed wildcard": func(c *config2.Config) { c.HealthPath = "/healthz/:" },
+	} {
+		config := config2.DefaultConfig()
+		update(&config)
+		router := gin.New()
+
+		err := New(router, config, []checks.Check{})
+		assert.ErrorIs(t, err, ErrInvalidRoute, name)
+		assert.NotErrorIs(t, err, ErrRouteConflict, name)
+		assert.Empty(t, router.Routes(), name)
+	}
+}
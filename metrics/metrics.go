@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+var statuses = []checks.Status{checks.StatusPass, checks.StatusWarn, checks.StatusFail}
+
+// Collector exports check results as Prometheus metrics. Add it to
+// config.Config.Hooks and register it on a prometheus.Registerer.
+type Collector struct {
+	checkStatus         *prometheus.GaugeVec
+	checkDuration       *prometheus.HistogramVec
+	checkFailures       *prometheus.CounterVec
+	consecutiveFailures *prometheus.GaugeVec
+	status              *prometheus.GaugeVec
+	requests            *prometheus.CounterVec
+
+	lock        sync.Mutex
+	consecutive map[string]float64
+}
+
+var _ config.Hook = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a collector whose metric names start with
+// namespace, or with "healthcheck" when namespace is empty.
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "healthcheck"
+	}
+
+	return &Collector{
+		checkStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "check_status",
+			Help:      "Latest status of a check, 1 for the current status and 0 for the others.",
+		}, []string{"check", "status"}),
+		checkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "check_duration_seconds",
+			Help:      "Duration of check runs.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"check"}),
+		checkFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "check_failures_total",
+			Help:      "Number of failed check runs.",
+		}, []string{"check"}),
+		consecutiveFailures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "check_consecutive_failures",
+			Help:      "Number of check runs that failed since the check last passed.",
+		}, []string{"check"}),
+		status: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "status",
+			Help:      "Latest aggregated status, 1 for the current status and 0 for the others.",
+		}, []string{"status"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of served health requests by aggregated status.",
+		}, []string{"status"}),
+		consecutive: make(map[string]float64),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.checkStatus.Describe(ch)
+	c.checkDuration.Describe(ch)
+	c.checkFailures.Describe(ch)
+	c.consecutiveFailures.Describe(ch)
+	c.status.Describe(ch)
+	c.requests.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.checkStatus.Collect(ch)
+	c.checkDuration.Collect(ch)
+	c.checkFailures.Collect(ch)
+	c.consecutiveFailures.Collect(ch)
+	c.status.Collect(ch)
+	c.requests.Collect(ch)
+}
+
+func (c *Collector) StartEvaluation(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (c *Collector) EndEvaluation(ctx context.Context, status checks.Status) {}
+
+func (c *Collector) StartCheck(ctx context.Context, name string) context.Context {
+	return ctx
+}
+
+func (c *Collector) EndCheck(ctx context.Context, name string, status checks.Status, err error, duration time.Duration) {
+	setStatus(c.checkStatus, status, name)
+	c.checkDuration.WithLabelValues(name).Observe(duration.Seconds())
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if status == checks.StatusFail {
+		c.checkFailures.WithLabelValues(name).Inc()
+		c.consecutive[name]++
+	} else {
+		c.consecutive[name] = 0
+	}
+	c.consecutiveFailures.WithLabelValues(name).Set(c.consecutive[name])
+}
+
+// Served counts every response. The aggregated status is only taken from
+// full evaluations of the health endpoint, not from filtered requests,
+// single checks or probes.
+func (c *Collector) Served(ctx context.Context, status checks.Status) {
+	if scope := config.ScopeOf(ctx); scope.Full && scope.Probe == "" {
+		setStatus(c.status, status)
+	}
+	c.requests.WithLabelValues(string(status)).Inc()
+}
+
+func setStatus(gauge *prometheus.GaugeVec, status checks.Status, labels ...string) {
+	for _, s := range statuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		gauge.WithLabelValues(append(labels, string(s))...).Set(value)
+	}
+}
+
+// Handler serves the metrics of gatherer, e.g. next to the health endpoint.
+func Handler(gatherer prometheus.Gatherer) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
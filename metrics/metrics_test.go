@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/controllers"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type ControlledCheck struct{ willPass bool }
+
+func (c *ControlledCheck) Pass() bool {
+	return c.willPass
+}
+
+func (c *ControlledCheck) Name() string {
+	return "controlled"
+}
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector("")
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	conf := config.DefaultConfig()
+	conf.Hooks = []config.Hook{collector}
+
+	controlled := &ControlledCheck{willPass: false}
+	router := gin.New()
+	router.GET(conf.HealthPath, controllers.HealthcheckController([]checks.Check{controlled}, conf))
+	router.GET("/metrics", Handler(registry))
+
+	get(router, conf.HealthPath)
+	get(router, conf.HealthPath)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.checkStatus.WithLabelValues("controlled", "fail")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(collector.checkStatus.WithLabelValues("controlled", "pass")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.checkFailures.WithLabelValues("controlled")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.consecutiveFailures.WithLabelValues("controlled")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.status.WithLabelValues("fail")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.requests.WithLabelValues("fail")))
+
+	controlled.willPass = true
+	get(router, conf.HealthPath)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.checkStatus.WithLabelValues("controlled", "pass")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.checkFailures.WithLabelValues("controlled")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(collector.consecutiveFailures.WithLabelValues("controlled")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.status.WithLabelValues("pass")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("pass")))
+
+	res := get(router, "/metrics")
+	assert.Equal(t, 200, res.Code)
+	assert.True(t, strings.Contains(res.Body.String(), `healthcheck_check_duration_seconds_count{check="controlled"} 3`))
+}
+
+func TestCollectorAggregateOfFullEvaluations(t *testing.T) {
+	collector := NewCollector("")
+	conf := config.DefaultConfig()
+	conf.Hooks = []config.Hook{collector}
+
+	checkList := []checks.Check{
+		checks.Configure(&ControlledCheck{willPass: false}, checks.WithProbes(checks.Readiness)),
+		checks.Configure(checks.NewContextCheck(context.Background(), "a"), checks.WithTags("a"), checks.WithProbes(checks.Liveness)),
+	}
+	router := gin.New()
+	router.GET(conf.HealthPath, controllers.HealthcheckController(checkList, conf))
+	router.GET(conf.HealthPath+"/:check", controllers.CheckController(checkList, conf))
+	router.GET("/livez", controllers.ProbeController(checks.Liveness, checkList, conf))
+
+	assert.Equal(t, 503, get(router, conf.HealthPath).Code)
+	assert.Equal(t, 200, get(router, conf.HealthPath+"/a").Code)
+	assert.Equal(t, 200, get(router, conf.HealthPath+"?include=a").Code)
+	assert.Equal(t, 200, get(router, "/livez").Code)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.status.WithLabelValues("fail")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(collector.status.WithLabelValues("pass")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(collector.requests.WithLabelValues("pass")))
+}
+
+func TestCollectorNamespace(t *testing.T) {
+	collector := NewCollector("myapp")
+	collector.Served(context.Background(), checks.StatusPass)
+
+	count, err := testutil.GatherAndCount(registryWith(t, collector), "myapp_requests_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func registryWith(t *testing.T, collector prometheus.Collector) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+	return registry
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(res, req)
+	return res
+}
@@ -0,0 +1,248 @@
+// Package nethttp serves checks with net/http handlers, for applications
+// that do not use gin. The handlers respond with the same bodies and status
+// codes as the gin controllers, which are built on them.
+package nethttp
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+)
+
+// CheckParam is the path value holding the ID or name of the check
+// evaluated by CheckHandler.
+const CheckParam = "check"
+
+const jsonContentType = "application/json; charset=utf-8"
+
+// New returns a handler serving the health endpoint at config.HealthPath,
+// and an endpoint evaluating a single check by its ID or name below it. It
+// returns an error if two checks have the same ID, or if checks depend on
+// unknown checks or on each other in a cycle.
+func New(config config.Config, checkList []checks.Check) (http.Handler, error) {
+	if err := checks.Validate(checkList); err != nil {
+		return nil, err
+	}
+
+	c := checker.New(checkList, config)
+	mux := http.NewServeMux()
+	mux.Handle(config.Method+" "+config.HealthPath, Handler(c))
+	mux.Handle(config.Method+" "+checkPattern(config.HealthPath), CheckHandler(c))
+	return mux, nil
+}
+
+// checkPattern is the pattern of the endpoint evaluating a single check
+// below healthPath.
+func checkPattern(healthPath string) string {
+	return strings.TrimSuffix(healthPath, "/") + "/{" + CheckParam + "}"
+}
+
+// Handler serves the reports of r. The include, exclude and deep query
+// parameters are passed to r as a checker.Filter.
+func Handler(r checker.Runner) http.Handler {
+	return handler(r, "")
+}
+
+func handler(r checker.Runner, probe checks.Probe) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		report, ok := evaluateRequest(w, req, r)
+		if ok {
+			respond(w, req, r.Config(), scopeOf(report, probe), report)
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// ProbeHandler returns the handler of one probe type, see checker.NewProbe.
+// The startup probe keeps passing once all of its checks have passed.
+func ProbeHandler(probe checks.Probe, checkList []checks.Check, config config.Config) http.Handler {
+	c := checker.NewProbe(probe, checkList, config)
+	if probe != checks.Startup {
+		return handler(c, probe)
+	}
+
+	var lock sync.Mutex
+	var started *checker.Report
+
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		report := started
+		lock.Unlock()
+
+		if report == nil {
+			evaluated, ok := evaluateRequest(w, req, c)
+			if !ok {
+				return
+			}
+			report = &evaluated
+			// Only the evaluation of all startup checks can latch the
+			// probe, not one filtered by tags.
+			if report.Status != checks.StatusFail && report.Filter == nil {
+				lock.Lock()
+				started = report
+				lock.Unlock()
+			}
+		}
+
+		respond(w, req, c.Config(), scopeOf(*report, probe), *report)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// CheckHandler evaluates only the check whose ID (see checks.IDOf) or name
+// is in the CheckParam path value, e.g. of the pattern /healthz/{check}.
+// Unknown checks are answered with 404, references to more than one check
+// with 409, and deep checks require the access to deep checks.
+func CheckHandler(r checker.Runner) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		ref := req.PathValue(CheckParam)
+		check, err := r.Find(ref)
+		if errors.Is(err, checker.ErrAmbiguousCheck) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if checks.OptionsOf(check).Deep && !authorize(w, req, checker.Filter{Deep: true}, r.Config()) {
+			return
+		}
+
+		report, err := r.RunCheck(req.Context(), ref)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		respond(w, req, r.Config(), config.Scope{}, report)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// scopeOf is the scope of report of the endpoint of probe.
+func scopeOf(report checker.Report, probe checks.Probe) config.Scope {
+	return config.Scope{Full: report.Filter == nil, Probe: probe}
+}
+
+// evaluateRequest evaluates the checks selected by the filter of req. It
+// responds with 400 and returns false for unknown tags, and with 403 if
+// deep checks are not allowed.
+func evaluateRequest(w http.ResponseWriter, req *http.Request, r checker.Runner) (checker.Report, bool) {
+	filter := parseFilter(req)
+	if !authorize(w, req, filter, r.Config()) {
+		return checker.Report{}, false
+	}
+
+	report, err := r.RunFilter(req.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return checker.Report{}, false
+	}
+	return report, true
+}
+
+// parseFilter reads the include and exclude query parameters, which can be
+// repeated or hold comma separated tags, and the deep query parameter.
+func parseFilter(req *http.Request) checker.Filter {
+	query := req.URL.Query()
+	deep, _ := strconv.ParseBool(query.Get("deep"))
+	return checker.Filter{
+		Include: queryList(query["include"]),
+		Exclude: queryList(query["exclude"]),
+		Deep:    deep,
+	}
+}
+
+func queryList(query []string) []string {
+	var values []string
+	for _, value := range query {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// authorize responds with 403 and returns false if f asks for deep checks
+// and config.Config.DeepAccess denies req.
+func authorize(w http.ResponseWriter, req *http.Request, f checker.Filter, conf config.Config) bool {
+	if !f.Deep || conf.DeepAccess == nil || conf.DeepAccess(req) {
+		return true
+	}
+	writeJSON(w, jsonContentType, http.StatusForbidden, map[string]string{"error": "deep checks are not allowed"})
+	return false
+}
+
+// respond writes report with the status code configured for its status.
+func respond(w http.ResponseWriter, req *http.Request, conf config.Config, scope config.Scope, report checker.Report) {
+	ctx := config.WithScope(req.Context(), scope)
+	for _, hook := range conf.Hooks {
+		hook.Served(ctx, report.Status)
+	}
+
+	code := httpStatus(report.Status, conf)
+	if responseFormat(req, conf) == config.FormatHealthJSON {
+		writeJSON(w, checker.HealthJSONContentType, code, checker.NewHealthResponse(report, conf.Service))
+		return
+	}
+	writeJSON(w, jsonContentType, code, report)
+}
+
+// responseFormat picks the format asked for in the Accept header, falling
+// back to the configured one.
+func responseFormat(req *http.Request, conf config.Config) config.Format {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case checker.HealthJSONContentType:
+			return config.FormatHealthJSON
+		case "application/json":
+			return config.FormatDefault
+		}
+	}
+	return conf.Format
+}
+
+func httpStatus(status checks.Status, config config.Config) int {
+	switch status {
+	case checks.StatusFail:
+		return config.StatusNotOK
+	case checks.StatusWarn:
+		if config.StatusDegraded != 0 {
+			return config.StatusDegraded
+		}
+	}
+	return config.StatusOK
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, jsonContentType, code, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, contentType string, code int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	w.Write(body)
+}
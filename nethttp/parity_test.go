@@ -0,0 +1,215 @@
+package nethttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	healthcheck "github.com/tavsec/gin-healthcheck/v2"
+	"github.com/tavsec/gin-healthcheck/v2/checker"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/controllers"
+	"github.com/tavsec/gin-healthcheck/v2/nethttp"
+)
+
+type resultCheck struct {
+	name string
+	err  error
+}
+
+func (r resultCheck) Pass() bool {
+	return r.err == nil
+}
+
+func (r resultCheck) Check(ctx context.Context) error {
+	return r.err
+}
+
+func (r resultCheck) Name() string {
+	return r.name
+}
+
+var (
+	up       = resultCheck{name: "up"}
+	down     = resultCheck{name: "down", err: errors.New("down")}
+	degraded = resultCheck{name: "degraded", err: checks.Warn(errors.New("slow"))}
+)
+
+type request struct {
+	path   string
+	header http.Header
+}
+
+// assertParity asserts that gin and h respond to every request with the
+// same status code, content type and body, apart from durations and times.
+func assertParity(t *testing.T, router *gin.Engine, h http.Handler, requests []request) {
+	t.Helper()
+
+	for _, r := range requests {
+		ginResponse := serve(router, r)
+		httpResponse := serve(h, r)
+
+		assert.Equal(t, ginResponse.Code, httpResponse.Code, r.path)
+		assert.Equal(t, ginResponse.Header().Get("Content-Type"), httpResponse.Header().Get("Content-Type"), r.path)
+		assert.Equal(t, normalize(t, ginResponse.Body.Bytes()), normalize(t, httpResponse.Body.Bytes()), r.path)
+	}
+}
+
+func serve(h http.Handler, r request) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, r.path, nil)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// normalize decodes body and removes the values that differ between runs.
+func normalize(t *testing.T, body []byte) any {
+	t.Helper()
+
+	var v any
+	assert.NoError(t, json.Unmarshal(body, &v), string(body))
+	return removeTimes(v)
+}
+
+func removeTimes(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range []string{"duration", "wait", "timestamp", "age", "time", "observedValue"} {
+			delete(v, key)
+		}
+		for key, value := range v {
+			v[key] = removeTimes(value)
+		}
+	case []any:
+		for idx, value := range v {
+			v[idx] = removeTimes(value)
+		}
+	}
+	return v
+}
+
+func TestParity(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.StatusDegraded = 207
+	conf.Service = config.ServiceInfo{ServiceID: "api"}
+	conf.DeepAccess = func(r *http.Request) bool {
+		return r.Header.Get("X-Deep") != ""
+	}
+
+	checkList := []checks.Check{
+		checks.Configure(up, checks.WithTags("db"), checks.WithID("db")),
+		checks.Configure(degraded, checks.WithTags("cache"), checks.WithComponentType("datastore")),
+		checks.Configure(down, checks.Optional(), checks.WithTags("queue")),
+		checks.Configure(resultCheck{name: "deep", err: errors.New("unreachable")}, checks.Deep()),
+	}
+
+	router := gin.New()
+	assert.NoError(t, healthcheck.New(router, conf, checkList))
+	h, err := nethttp.New(conf, checkList)
+	assert.NoError(t, err)
+
+	deep := http.Header{"X-Deep": {"1"}}
+	assertParity(t, router, h, []request{
+		{path: "/healthz"},
+		{path: "/healthz", header: http.Header{"Accept": {checker.HealthJSONContentType}}},
+		{path: "/healthz?include=db"},
+		{path: "/healthz?include=db,queue&exclude=cache"},
+		{path: "/healthz?include=unknown"},
+		{path: "/healthz?deep=true"},
+		{path: "/healthz?deep=true", header: deep},
+		{path: "/healthz/db"},
+		{path: "/healthz/down"},
+		{path: "/healthz/deep"},
+		{path: "/healthz/deep", header: deep},
+		{path: "/healthz/unknown"},
+	})
+}
+
+func TestParityFailing(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.ErrorDetail = config.ErrorDetailSummary
+	checkList := []checks.Check{
+		up,
+		checks.Configure(down, checks.WithID("down")),
+		checks.Configure(resultCheck{name: "dependent"}, checks.DependsOn("down")),
+	}
+
+	router := gin.New()
+	assert.NoError(t, healthcheck.New(router, conf, checkList))
+	h, err := nethttp.New(conf, checkList)
+	assert.NoError(t, err)
+
+	assertParity(t, router, h, []request{
+		{path: "/healthz"},
+		{path: "/healthz", header: http.Header{"Accept": {checker.HealthJSONContentType}}},
+		{path: "/healthz/dependent"},
+	})
+
+	var report checker.Report
+	assert.NoError(t, json.Unmarshal(serve(h, request{path: "/healthz"}).Body.Bytes(), &report))
+	assert.Equal(t, "failed", report.Checks[1].Error)
+	assert.Equal(t, `skipped: dependency "down" failed`, report.Checks[2].Error)
+}
+
+func TestParityProbes(t *testing.T) {
+	conf := config.DefaultConfig()
+	checkList := []checks.Check{
+		checks.Configure(up, checks.WithProbes(checks.Liveness, checks.Startup)),
+		down,
+	}
+
+	router := gin.New()
+	assert.NoError(t, healthcheck.NewProbes(router, conf, checkList))
+	mux := http.NewServeMux()
+	for _, probe := range []checks.Probe{checks.Liveness, checks.Readiness, checks.Startup} {
+		mux.Handle(conf.Probe(probe).HealthPath, nethttp.ProbeHandler(probe, checkList, conf))
+	}
+
+	assertParity(t, router, mux, []request{
+		{path: "/livez"},
+		{path: "/readyz"},
+		{path: "/startupz"},
+	})
+}
+
+func TestParityScheduled(t *testing.T) {
+	conf := config.DefaultConfig()
+	conf.Scheduler.Interval = time.Hour
+	scheduler := controllers.NewScheduler([]checks.Check{up, down}, conf)
+
+	router := gin.New()
+	router.GET("/healthz", controllers.ScheduledController(scheduler))
+	router.GET("/healthz/:check", controllers.ScheduledCheckController(scheduler))
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", nethttp.Handler(scheduler))
+	mux.Handle("/healthz/{check}", nethttp.CheckHandler(scheduler))
+
+	requests := []request{{path: "/healthz"}, {path: "/healthz/up"}}
+	assertParity(t, router, mux, requests)
+
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+	assert.Eventually(t, func() bool {
+		report, err := scheduler.RunCheck(context.Background(), "down")
+		return err == nil && !report.Checks[0].Timestamp.IsZero()
+	}, time.Second, time.Millisecond)
+	assertParity(t, router, mux, requests)
+}
+
+func TestNewRejectsDependencyCycles(t *testing.T) {
+	_, err := nethttp.New(config.DefaultConfig(), []checks.Check{
+		checks.Configure(up, checks.DependsOn("down")),
+		checks.Configure(down, checks.DependsOn("up")),
+	})
+	assert.ErrorIs(t, err, checks.ErrDependencyCycle)
+}
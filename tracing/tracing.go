@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/tavsec/gin-healthcheck/v2/tracing"
+
+const (
+	NameKey     = attribute.Key("healthcheck.name")
+	StatusKey   = attribute.Key("healthcheck.status")
+	DurationKey = attribute.Key("healthcheck.duration_ms")
+	ErrorKey    = attribute.Key("healthcheck.error")
+)
+
+// Hook wraps every health evaluation in a span, with a child span per
+// check. Add it to config.Config.Hooks.
+type Hook struct {
+	// ErrorDetail controls the ErrorKey attribute of failed checks, like
+	// config.Config.ErrorDetail does for the response. NewHook sets it to
+	// config.ErrorDetailSummary.
+	ErrorDetail config.ErrorDetail
+
+	tracer trace.Tracer
+}
+
+var _ config.Hook = (*Hook)(nil)
+
+// NewHook creates a hook using provider, or the global tracer provider
+// when provider is nil.
+func NewHook(provider trace.TracerProvider) *Hook {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Hook{
+		ErrorDetail: config.ErrorDetailSummary,
+		tracer:      provider.Tracer(instrumentationName),
+	}
+}
+
+// StartEvaluation starts the evaluation span as a child of the span in ctx,
+// which is the span of the incoming request when it is traced.
+func (h *Hook) StartEvaluation(ctx context.Context) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "healthcheck", trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx
+}
+
+func (h *Hook) EndEvaluation(ctx context.Context, status checks.Status) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(StatusKey.String(string(status)))
+	if status == checks.StatusFail {
+		span.SetStatus(codes.Error, "healthcheck failed")
+	}
+	span.End()
+}
+
+func (h *Hook) StartCheck(ctx context.Context, name string) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "healthcheck "+name, trace.WithAttributes(NameKey.String(name)))
+	return ctx
+}
+
+func (h *Hook) EndCheck(ctx context.Context, name string, status checks.Status, err error, duration time.Duration) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		StatusKey.String(string(status)),
+		DurationKey.Float64(float64(duration)/float64(time.Millisecond)),
+	)
+	if err != nil {
+		if message := h.ErrorDetail.Message(err); message != "" {
+			span.SetAttributes(ErrorKey.String(message))
+		}
+		span.RecordError(err)
+	}
+	if status == checks.StatusFail {
+		span.SetStatus(codes.Error, errorDescription(err))
+	}
+	span.End()
+}
+
+func (h *Hook) Served(ctx context.Context, status checks.Status) {}
+
+func errorDescription(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Middleware extracts the trace context of incoming requests with
+// propagator, or the global propagator when it is nil, so that health
+// evaluations are part of the caller's trace. It is not needed when the
+// engine is already instrumented, e.g. with otelgin.
+func Middleware(propagator propagation.TextMapPropagator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := propagator
+		if p == nil {
+			p = otel.GetTextMapPropagator()
+		}
+		ctx := p.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tavsec/gin-healthcheck/v2/checks"
+	"github.com/tavsec/gin-healthcheck/v2/config"
+	"github.com/tavsec/gin-healthcheck/v2/controllers"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type FailingCheck struct{}
+
+func (c FailingCheck) Pass() bool {
+	return false
+}
+
+func (c FailingCheck) Name() string {
+	return "failing"
+}
+
+func TestHook(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	conf := config.DefaultConfig()
+	conf.Hooks = []config.Hook{NewHook(provider)}
+
+	router := gin.New()
+	router.Use(Middleware(propagation.TraceContext{}))
+	router.GET(conf.HealthPath, controllers.HealthcheckController([]checks.Check{
+		FailingCheck{},
+		checks.NewContextCheck(t.Context(), "context"),
+	}, conf))
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", conf.HealthPath, nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(res, req)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	evaluation := byName["healthcheck"]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", evaluation.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", evaluation.Parent.SpanID().String())
+	assert.True(t, evaluation.Parent.IsRemote())
+	assert.Equal(t, codes.Error, evaluation.Status.Code)
+	assert.Contains(t, evaluation.Attributes, StatusKey.String("fail"))
+
+	failing := byName["healthcheck failing"]
+	assert.Equal(t, evaluation.SpanContext.SpanID(), failing.Parent.SpanID())
+	assert.Contains(t, failing.Attributes, NameKey.String("failing"))
+	assert.Contains(t, failing.Attributes, StatusKey.String("fail"))
+	assert.Equal(t, codes.Error, failing.Status.Code)
+	assert.Equal(t, "check failed", failing.Status.Description)
+	assert.Contains(t, failing.Attributes, ErrorKey.String("failed"))
+	assert.Len(t, failing.Events, 1)
+
+	passing := byName["healthcheck context"]
+	assert.Equal(t, evaluation.SpanContext.SpanID(), passing.Parent.SpanID())
+	assert.Contains(t, passing.Attributes, StatusKey.String("pass"))
+	assert.Equal(t, codes.Unset, passing.Status.Code)
+	assert.Empty(t, passing.Events)
+	for _, attr := range passing.Attributes {
+		assert.NotEqual(t, ErrorKey, attr.Key)
+	}
+}
+
+func TestHookErrorDetail(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	hook := NewHook(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	hook.ErrorDetail = config.ErrorDetailFull
+
+	conf := config.DefaultConfig()
+	conf.Hooks = []config.Hook{hook}
+
+	router := gin.New()
+	router.GET(conf.HealthPath, controllers.HealthcheckController([]checks.Check{FailingCheck{}}, conf))
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", conf.HealthPath, nil)
+	router.ServeHTTP(res, req)
+
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "healthcheck failing" {
+			assert.Contains(t, span.Attributes, ErrorKey.String("check failed"))
+			return
+		}
+	}
+	t.Error("no span of the failing check")
+}